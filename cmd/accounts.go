@@ -1,7 +1,8 @@
 package cmd
 
 import (
-	"github.com/TheFeedFactory/tff-cli/internal/api"
+	"context"
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
 )
 
 type AccountsCmd struct {
@@ -13,24 +14,24 @@ type AccountsMeCmd struct {
 	JSON bool `short:"j" help:"Output as JSON."`
 }
 
-func (c *AccountsMeCmd) Run(client *api.Client) error {
-	body, err := client.GetAccountMe()
+func (c *AccountsMeCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	body, err := client.GetAccountMe(ctx)
 	if err != nil {
 		return err
 	}
 
-	return printRawJSON(body)
+	return renderGenericObject(body, c.JSON)
 }
 
 type AccountsListCmd struct {
 	JSON bool `short:"j" help:"Output as JSON."`
 }
 
-func (c *AccountsListCmd) Run(client *api.Client) error {
-	body, err := client.ListAccounts()
+func (c *AccountsListCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	body, err := client.ListAccounts(ctx)
 	if err != nil {
 		return err
 	}
 
-	return printRawJSON(body)
+	return renderGeneric(body, c.JSON, "No accounts found.")
 }
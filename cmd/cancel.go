@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// stopError is returned by a command that was interrupted (Ctrl-C, SIGTERM) or
+// ran into --timeout. Its message says how far the command got.
+type stopError struct {
+	msg string
+	err error
+}
+
+func (e *stopError) Error() string { return e.msg }
+func (e *stopError) Unwrap() error { return e.err }
+
+// stopReason describes why ctx is done.
+func stopReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "timed out"
+	}
+	return "interrupted"
+}
+
+// stopped returns the error for a command stopped by ctx after part of its work,
+// e.g. stopped(ctx, "after translating %d of %d events", n, total).
+func stopped(ctx context.Context, format string, args ...interface{}) error {
+	return &stopError{
+		msg: stopReason(ctx) + " " + fmt.Sprintf(format, args...),
+		err: ctx.Err(),
+	}
+}
+
+// StoppedError rewrites the error of a command whose context is done. Errors that
+// already report progress are kept; others, such as a request failing with
+// "context canceled", become a plain "interrupted" or "timed out".
+func StoppedError(ctx context.Context, err error) error {
+	var se *stopError
+	if errors.As(err, &se) {
+		return err
+	}
+	return &stopError{msg: stopReason(ctx), err: ctx.Err()}
+}
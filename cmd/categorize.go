@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type ResourceCategorizeCmd[K resourceKind] struct {
+	ID     string   `arg:"" help:"ID of the ${singular}."`
+	Add    []string `sep:"none" placeholder:"CATEGORY" help:"Add a type category, by ID (2.1.3) or by label in --lang (Concert, or part of a label if that matches only one category). Repeatable."`
+	Remove []string `sep:"none" placeholder:"CATEGORY" help:"Remove a type category or category property, by ID or label. Repeatable."`
+	Set    []string `sep:"none" placeholder:"CATEGORY=VALUE" help:"Set the value of a category property, adding the property if the ${singular} doesn't have it, e.g. --set 3.4.1=120. Repeatable."`
+	Lang   string   `default:"nl" help:"Language of category labels, for lookup and output (nl, en, de). Default: nl."`
+	JSON   bool     `short:"j" help:"Output JSON instead of a table when listing."`
+	DryRun bool     `name:"dry-run" help:"Show the new categories without saving them."`
+	Force  bool     `short:"f" help:"Save without a confirmation prompt."`
+}
+
+// categoryNode is a category of the ontology, with the entity type and
+// deprecation it inherits from its parents.
+type categoryNode struct {
+	cat        *feedfactory.Categorization
+	label      string
+	parent     string
+	entityType string
+	deprecated bool
+}
+
+// id returns the ID assigned to resources: the categorization ID, or the cnetID
+// for categories without one, as in 'dictionary categories'.
+func (n *categoryNode) id() string {
+	if n.cat.ID != nil && *n.cat.ID != "" {
+		return *n.cat.ID
+	}
+	return n.cat.CnetID
+}
+
+// is reports whether id refers to the category by either of its IDs.
+func (n *categoryNode) is(id string) bool {
+	return id != "" && (id == n.cat.CnetID || id == n.id())
+}
+
+func (n *categoryNode) leaf() bool {
+	return len(n.cat.Children) == 0
+}
+
+// categoryIndex is the ontology flattened for lookups.
+type categoryIndex []*categoryNode
+
+func newCategoryIndex(ontology *feedfactory.Ontology, lang string) categoryIndex {
+	var index categoryIndex
+	var walk func(cats []feedfactory.Categorization, parent, entityType string, deprecated bool)
+	walk = func(cats []feedfactory.Categorization, parent, entityType string, deprecated bool) {
+		for i := range cats {
+			cat := &cats[i]
+			if cat.EntityType != "" {
+				entityType = cat.EntityType
+			}
+			n := &categoryNode{cat: cat, label: cat.Name, parent: parent, entityType: entityType, deprecated: deprecated || cat.IsDeprecated()}
+			for _, t := range cat.Translations {
+				if t.Lang == lang {
+					n.label = t.Label
+					break
+				}
+			}
+			index = append(index, n)
+			walk(cat.Children, n.label, entityType, n.deprecated)
+		}
+	}
+	walk(ontology.Categorizations, "", "", false)
+	return index
+}
+
+// byID returns the category with the given ID, or nil.
+func (idx categoryIndex) byID(id string) *categoryNode {
+	for _, n := range idx {
+		if n.is(id) {
+			return n
+		}
+	}
+	return nil
+}
+
+// lookup finds the category to assign for name, an ID or a label: an exact
+// label match, or else the only label that contains name. It checks that the
+// category can be assigned to resources of entityType.
+func (idx categoryIndex) lookup(name, entityType string) (*categoryNode, error) {
+	n := idx.byID(name)
+	if n == nil {
+		var exact, partial []*categoryNode
+		for _, c := range idx {
+			if !c.leaf() || c.deprecated || c.entityType != entityType {
+				continue
+			}
+			switch {
+			case strings.EqualFold(c.label, name):
+				exact = append(exact, c)
+			case strings.Contains(strings.ToLower(c.label), strings.ToLower(name)):
+				partial = append(partial, c)
+			}
+		}
+		matches := exact
+		if len(matches) == 0 {
+			matches = partial
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no category matches %q (see tff dictionary categories)", name)
+		case 1:
+			return matches[0], nil
+		}
+		var names []string
+		for i, m := range matches {
+			if i == 10 {
+				names = append(names, fmt.Sprintf("and %d more", len(matches)-i))
+				break
+			}
+			names = append(names, fmt.Sprintf("%s (%s, %s)", m.id(), m.label, m.parent))
+		}
+		return nil, fmt.Errorf("%q matches several categories: %s", name, strings.Join(names, "; "))
+	}
+
+	switch {
+	case n.entityType != entityType:
+		return nil, fmt.Errorf("category %s (%s) is for %s, not %s", name, n.label, n.entityType, entityType)
+	case n.deprecated:
+		return nil, fmt.Errorf("category %s (%s) is deprecated", name, n.label)
+	case !n.leaf():
+		return nil, fmt.Errorf("category %s (%s) is a group; pick one of its subcategories", name, n.label)
+	}
+	return n, nil
+}
+
+// categoryRow is a category of a resource in the output of 'categorize', with
+// the change an edit makes to it in the preview.
+type categoryRow struct {
+	ID      string `json:"id"`
+	Label   string `json:"label,omitempty"`
+	Kind    string `json:"kind"`
+	Value   string `json:"value,omitempty"`
+	Default bool   `json:"default,omitempty"`
+	Change  string `json:"change,omitempty"`
+}
+
+var categoryColumns = []column[categoryRow]{
+	{Name: "id", Value: func(r categoryRow) string { return r.ID }},
+	{Name: "label", Width: 40, Value: func(r categoryRow) string { return r.Label }},
+	{Name: "kind", Value: func(r categoryRow) string {
+		if r.Default {
+			return r.Kind + " (default)"
+		}
+		return r.Kind
+	}},
+	{Name: "value", Width: 30, Value: func(r categoryRow) string { return r.Value }},
+}
+
+var categoryChangeColumn = column[categoryRow]{Name: "change", Value: func(r categoryRow) string { return r.Change }}
+
+func (c *ResourceCategorizeCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	entityType := feedfactory.OntologyEntityType(d.Endpoint)
+
+	data, err := client.GetOntology(ctx)
+	if err != nil {
+		return err
+	}
+	ontology, err := feedfactory.ParseOntology(data)
+	if err != nil {
+		return err
+	}
+	index := newCategoryIndex(ontology, c.Lang)
+
+	r, err := getResourceDoc(ctx, client, d, c.ID)
+	if err != nil {
+		return err
+	}
+	if r.Categories == nil {
+		r.Categories = &feedfactory.ItemCategories{}
+	}
+	before := categoryRows(r.Categories, index)
+
+	if len(c.Add) == 0 && len(c.Remove) == 0 && len(c.Set) == 0 {
+		return view[categoryRow]{
+			Columns:  categoryColumns,
+			Rows:     before,
+			Document: r.Categories,
+			Empty:    fmt.Sprintf("The %s has no categories.", d.Singular),
+		}.render(c.JSON)
+	}
+
+	if err := c.apply(r.Categories, index, entityType); err != nil {
+		return err
+	}
+	rows, changed := categoryChanges(before, categoryRows(r.Categories, index))
+	if !changed {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	fmt.Printf("New categories of %s %s:\n\n", d.Singular, c.ID)
+	err = view[categoryRow]{
+		Columns: append(append([]column[categoryRow](nil), categoryColumns...), categoryChangeColumn),
+		Rows:    rows,
+		Empty:   fmt.Sprintf("The %s has no categories.", d.Singular),
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		fmt.Println("\nDry run: the categories were not saved.")
+		return nil
+	}
+	if !c.Force && !confirm(fmt.Sprintf("\nSave the new categories of %s %s?", d.Singular, c.ID)) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	doc, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := client.UpdateResource(ctx, d.Endpoint, c.ID, doc); err != nil {
+		return fmt.Errorf("saving categories: %w", err)
+	}
+	fmt.Printf("Saved the categories of %s %s.\n", d.Singular, c.ID)
+	return nil
+}
+
+// apply makes the changes of the flags to cats: removals first, so a category
+// can be replaced in one go.
+func (c *ResourceCategorizeCmd[K]) apply(cats *feedfactory.ItemCategories, index categoryIndex, entityType string) error {
+	for _, name := range c.Remove {
+		matches := func(id string) bool { return id == name }
+		if n := index.byID(name); n != nil {
+			matches = n.is
+		} else if !hasCategory(cats, name) {
+			n, err := index.lookup(name, entityType)
+			if err != nil {
+				return err
+			}
+			matches = n.is
+		}
+		removed := false
+		var wasDefault bool
+		types := cats.Types[:0]
+		for _, t := range cats.Types {
+			if matches(t.CatID) {
+				removed = true
+				wasDefault = wasDefault || t.IsDefault
+				continue
+			}
+			types = append(types, t)
+		}
+		cats.Types = types
+		props := cats.Categories[:0]
+		for _, p := range cats.Categories {
+			if matches(p.CatID) {
+				removed = true
+				continue
+			}
+			props = append(props, p)
+		}
+		cats.Categories = props
+		if !removed {
+			return fmt.Errorf("--remove %s: category is not assigned", name)
+		}
+		// Another type takes over as the default.
+		if wasDefault && len(cats.Types) > 0 {
+			cats.Types[0].IsDefault = true
+		}
+	}
+
+	for _, name := range c.Add {
+		n, err := index.lookup(name, entityType)
+		if err != nil {
+			return fmt.Errorf("--add %s: %w", name, err)
+		}
+		if hasType(cats, n) {
+			continue
+		}
+		// A first type is the default.
+		cats.Types = append(cats.Types, feedfactory.CategoryType{CatID: n.id(), IsDefault: len(cats.Types) == 0})
+	}
+
+	for _, s := range c.Set {
+		name, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("--set %q: use category=value", s)
+		}
+		n, err := index.lookup(name, entityType)
+		if err != nil {
+			return fmt.Errorf("--set %s: %w", name, err)
+		}
+		found := false
+		for i := range cats.Categories {
+			p := &cats.Categories[i]
+			if !n.is(p.CatID) {
+				continue
+			}
+			found = true
+			if p.Value != value {
+				// The value ID refers to the old value.
+				p.Value, p.ValueID = value, ""
+			}
+		}
+		if !found {
+			cats.Categories = append(cats.Categories, feedfactory.CategoryProperty{CatID: n.id(), Value: value})
+		}
+	}
+	return nil
+}
+
+func hasCategory(cats *feedfactory.ItemCategories, id string) bool {
+	for _, t := range cats.Types {
+		if t.CatID == id {
+			return true
+		}
+	}
+	for _, p := range cats.Categories {
+		if p.CatID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasType(cats *feedfactory.ItemCategories, n *categoryNode) bool {
+	for _, t := range cats.Types {
+		if n.is(t.CatID) {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryRows lists the types and category properties of cats, labelled from
+// the ontology.
+func categoryRows(cats *feedfactory.ItemCategories, index categoryIndex) []categoryRow {
+	label := func(id string) string {
+		n := index.byID(id)
+		switch {
+		case n == nil:
+			return ""
+		case n.deprecated:
+			return n.label + " (deprecated)"
+		}
+		return n.label
+	}
+	rows := []categoryRow{}
+	for _, t := range cats.Types {
+		rows = append(rows, categoryRow{ID: t.CatID, Label: label(t.CatID), Kind: "type", Default: t.IsDefault})
+	}
+	for _, p := range cats.Categories {
+		rows = append(rows, categoryRow{ID: p.CatID, Label: label(p.CatID), Kind: "property", Value: p.Value})
+	}
+	return rows
+}
+
+// categoryChanges marks the rows of after that are new or changed compared to
+// before, followed by the rows that were removed, and reports whether anything
+// changed.
+func categoryChanges(before, after []categoryRow) ([]categoryRow, bool) {
+	key := func(r categoryRow) string { return r.Kind + " " + r.ID }
+	old := map[string]categoryRow{}
+	for _, r := range before {
+		old[key(r)] = r
+	}
+	changed := false
+	rows := make([]categoryRow, 0, len(after))
+	for _, r := range after {
+		prev, ok := old[key(r)]
+		switch {
+		case !ok:
+			r.Change = "added"
+		case prev.Value != r.Value:
+			r.Change = "changed"
+		case prev.Default != r.Default:
+			r.Change = "default"
+		}
+		changed = changed || r.Change != ""
+		delete(old, key(r))
+		rows = append(rows, r)
+	}
+	for _, r := range before {
+		if _, ok := old[key(r)]; ok {
+			r.Change = "removed"
+			rows = append(rows, r)
+			changed = true
+		}
+	}
+	return rows, changed
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/TheFeedFactory/tff-cli/internal/linkcheck"
+)
+
+type CheckCmd struct {
+	Links CheckLinksCmd `cmd:"" help:"Check all URLs on resources (URLs, contact URLs and media) and report broken links grouped by owner. Optionally add a marker to affected resources."`
+}
+
+type CheckLinksCmd struct {
+	Types          []string      `arg:"" optional:"" help:"Resource types to check: events, locations, routes, venues, eventgroups. Default: all types."`
+	RequestTimeout time.Duration `name:"request-timeout" default:"10s" help:"Timeout per URL, including redirects. Default: 10s."`
+	Concurrency    int           `default:"16" help:"Maximum number of URLs checked at the same time. Default: 16."`
+	PerHost        int           `name:"per-host" default:"2" help:"Maximum number of simultaneous requests to one host. Default: 2."`
+	HostDelay      time.Duration `name:"host-delay" default:"250ms" help:"Minimum time between two requests to the same host. Default: 250ms."`
+	CacheTTL       time.Duration `name:"cache-ttl" default:"24h" help:"Reuse results from earlier runs that are younger than this. Default: 24h."`
+	NoCache        bool          `name:"no-cache" help:"Check every URL again, ignoring and not updating the result cache."`
+	Mark           string        `help:"Add this marker (e.g. brokenlink) to every resource with a broken link."`
+	All            bool          `help:"Report every checked URL, not just broken ones."`
+	JSON           bool          `short:"j" help:"Output results as JSON."`
+	ScanFlags
+}
+
+// linkRef is a URL found on a resource.
+type linkRef struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Owner     string `json:"owner"`
+	Field     string `json:"field"`
+	URL       string `json:"url"`
+	Status    int    `json:"status,omitempty"`
+	Final     string `json:"finalUrl,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Broken    bool   `json:"broken"`
+	Redirects int    `json:"redirects,omitempty"`
+}
+
+func (c *CheckLinksCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	types, err := c.scanTypes(c.Types)
+	if err != nil {
+		return err
+	}
+
+	var refs []linkRef
+	for _, t := range types {
+		resources, err := c.scan(ctx, client, t)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", t, err)
+		}
+		for _, r := range resources {
+			refs = append(refs, extractLinks(t, r)...)
+		}
+	}
+
+	if len(refs) == 0 {
+		fmt.Println("No URLs found.")
+		return nil
+	}
+
+	opts := linkcheck.Options{
+		Timeout:     c.RequestTimeout,
+		Concurrency: c.Concurrency,
+		PerHost:     c.PerHost,
+		HostDelay:   c.HostDelay,
+	}
+	var cache *linkcheck.Cache
+	if !c.NoCache {
+		path, err := linkcheck.DefaultCachePath()
+		if err != nil {
+			return err
+		}
+		if cache, err = linkcheck.OpenCache(path, c.CacheTTL); err != nil {
+			return err
+		}
+		opts.Cache = cache
+	}
+
+	urls := make([]string, len(refs))
+	for i, ref := range refs {
+		urls[i] = ref.URL
+	}
+	results := linkcheck.New(opts).CheckAll(ctx, urls, func(done, total int) {
+		fmt.Fprintf(os.Stderr, "Checked %d of %d URLs\r", done, total)
+	})
+	fmt.Fprintln(os.Stderr)
+
+	if cache != nil {
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if ctx.Err() != nil {
+		unique := map[string]bool{}
+		for _, u := range urls {
+			unique[u] = true
+		}
+		total := len(unique)
+		if cache != nil {
+			return stopped(ctx, "after checking %d of %d URLs; the results so far are cached", len(results), total)
+		}
+		return stopped(ctx, "after checking %d of %d URLs", len(results), total)
+	}
+
+	var report []linkRef
+	brokenResources := map[string][]string{}
+	for _, ref := range refs {
+		res := results[ref.URL]
+		ref.Status = res.Status
+		ref.Final = res.FinalURL
+		ref.Error = res.Error
+		ref.Broken = res.Broken
+		ref.Redirects = len(res.Redirects)
+		if ref.Broken {
+			ids := brokenResources[ref.Type]
+			if len(ids) == 0 || ids[len(ids)-1] != ref.ID {
+				brokenResources[ref.Type] = append(ids, ref.ID)
+			}
+		}
+		if ref.Broken || c.All {
+			report = append(report, ref)
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Owner != report[j].Owner {
+			return report[i].Owner < report[j].Owner
+		}
+		return report[i].ID < report[j].ID
+	})
+
+	if c.Mark != "" {
+		if err := markResources(ctx, client, brokenResources, c.Mark); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput(c.JSON) {
+		if report == nil {
+			report = []linkRef{}
+		}
+		return printJSON(report)
+	}
+
+	broken := 0
+	for _, ids := range brokenResources {
+		broken += len(ids)
+	}
+	if len(report) == 0 {
+		fmt.Printf("All %d URLs are reachable.\n", len(results))
+		return nil
+	}
+
+	owner := "\x00"
+	var w *tabwriter.Writer
+	for _, ref := range report {
+		if ref.Owner != owner {
+			if w != nil {
+				w.Flush()
+				fmt.Println()
+			}
+			owner = ref.Owner
+			label := owner
+			if label == "" {
+				label = "(no owner)"
+			}
+			fmt.Printf("Owner: %s\n", label)
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "  TYPE\tID\tFIELD\tSTATUS\tURL")
+		}
+		status := fmt.Sprint(ref.Status)
+		if ref.Error != "" {
+			status = ref.Error
+		}
+		if !ref.Broken {
+			status = "OK " + status
+		}
+		url := ref.URL
+		if ref.Final != "" {
+			url += " -> " + ref.Final
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", ref.Type, ref.ID, ref.Field, truncate(status, 30), url)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d unique URLs checked, %d resources with broken links\n", len(results), broken)
+	return nil
+}
+
+// extractLinks returns all URLs on a resource.
+func extractLinks(resourceType string, r feedfactory.Resource) []linkRef {
+	var refs []linkRef
+	add := func(field, u string) {
+		if u != "" {
+			refs = append(refs, linkRef{Type: resourceType, ID: r.ID, Title: r.GetTitle(), Owner: r.Owner, Field: field, URL: u})
+		}
+	}
+	for _, u := range r.URLs {
+		add("url", u.URL)
+	}
+	if r.ContactInfo != nil {
+		for _, u := range r.ContactInfo.URLs {
+			add("contact", u.URL)
+		}
+	}
+	for _, m := range r.Media {
+		add("media", m.URL)
+	}
+	return refs
+}
+
+// markResources adds marker to the given resources, keyed by resource type.
+func markResources(ctx context.Context, client *feedfactory.Client, ids map[string][]string, marker string) error {
+	total, marked := 0, 0
+	for _, list := range ids {
+		total += len(list)
+	}
+	for resourceType, list := range ids {
+		for _, id := range list {
+			err := client.ModifyResource(ctx, resourceType, id, func(doc map[string]interface{}) error {
+				updateMarkers(doc, []string{marker}, nil)
+				return nil
+			})
+			if err != nil && ctx.Err() != nil {
+				return stopped(ctx, "after marking %d of %d resources with %q", marked, total, marker)
+			}
+			if err != nil {
+				return fmt.Errorf("marking %s %s: %w", resourceType, id, err)
+			}
+			marked++
+		}
+		fmt.Fprintf(os.Stderr, "Added marker %q to %d %s\n", marker, len(list), resourceType)
+	}
+	return nil
+}
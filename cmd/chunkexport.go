@@ -0,0 +1,561 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// chunkFlags are the flags of 'export --chunked', which builds the export
+// client-side from list requests instead of asking the API for one large file.
+type chunkFlags struct {
+	Chunked     bool   `help:"Split the export into chunks that are fetched in parallel and merged into one file client-side, for result sets too large for a single server-side export. The extension of --file picks the format: .xlsx, .csv or .ndjson (one JSON document per line). Events with a date range are split into date windows, other exports into windows of last modification (both --window); exports with --sort are split into pages (--chunk-size). A manifest records which chunks succeeded."`
+	Window      string `default:"1mo" help:"Length of a window with --chunked, e.g. 7d, 2w, 1mo or 1y: of event dates for events with --when or --date-from and --date-to, of last modification otherwise. Default: 1mo."`
+	ChunkSize   int    `name:"chunk-size" default:"500" help:"Number of ${plural} per chunk (and per request) with --chunked. Default: 500."`
+	Concurrency int    `default:"4" help:"Number of chunks fetched at the same time with --chunked. Default: 4."`
+	Manifest    string `help:"Path of the manifest written with --chunked. Default: the export file name followed by .manifest.json."`
+}
+
+// chunkAttempts is how often a request of a chunk is tried before the chunk fails.
+const chunkAttempts = 3
+
+// exportChunk is one part of a chunked export, as recorded in the manifest.
+type exportChunk struct {
+	Index int `json:"index"`
+	// From and To are the first and last day of a date window, or the start
+	// and end (exclusive, empty for the last one) of a modification window.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Page is the page number of a page chunk.
+	Page     *int   `json:"page,omitempty"`
+	Status   string `json:"status"`
+	Count    int    `json:"count"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+
+	results []json.RawMessage
+}
+
+// exportManifest describes a chunked export: the query, how it was split and
+// which chunks made it into the file.
+type exportManifest struct {
+	ResourceType string        `json:"resourceType"`
+	File         string        `json:"file"`
+	Format       string        `json:"format"`
+	Created      string        `json:"created"`
+	Query        url.Values    `json:"query"`
+	ChunkBy      string        `json:"chunkBy"`
+	Window       string        `json:"window,omitempty"`
+	ChunkSize    int           `json:"chunkSize"`
+	Hits         int           `json:"hits,omitempty"`
+	Chunks       []exportChunk `json:"chunks"`
+	Written      int           `json:"written"`
+	Duplicates   int           `json:"duplicates"`
+	Complete     bool          `json:"complete"`
+}
+
+// export runs a chunked export of the resources matching opts and params to file.
+func (f *chunkFlags) export(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, file string, opts feedfactory.ListOptions, params url.Values) error {
+	format, err := chunkedFormat(file)
+	if err != nil {
+		return err
+	}
+	if f.ChunkSize < 1 || f.ChunkSize > 5000 {
+		return fmt.Errorf("--chunk-size must be between 1 and 5000")
+	}
+	if f.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	manifestPath := f.Manifest
+	if manifestPath == "" {
+		manifestPath = file + ".manifest.json"
+	}
+
+	// Without --sort, anything but a date range is split by last modification.
+	byModified := opts.Sort == "" && (d.DateParams[0] == "" || params.Get(d.DateParams[0]) == "" || params.Get(d.DateParams[1]) == "")
+	// Oldest first, so pages don't shift when resources are added while the
+	// export runs and the order of the file is the same on every run.
+	if opts.Sort == "" {
+		opts.Sort = "created"
+		opts.Asc = true
+	}
+	opts.Size = f.ChunkSize
+
+	m := &exportManifest{
+		ResourceType: d.Endpoint,
+		File:         file,
+		Format:       format,
+		Created:      time.Now().In(apiLocation).Format(time.RFC3339),
+		Query:        opts.Params(),
+		ChunkSize:    f.ChunkSize,
+	}
+	for k, v := range params {
+		m.Query[k] = v
+	}
+	m.Query.Del("size")
+
+	var oldest time.Time
+	if byModified {
+		if oldest, err = oldestModification(ctx, client, d, opts, params); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case d.DateParams[0] != "" && params.Get(d.DateParams[0]) != "" && params.Get(d.DateParams[1]) != "":
+		m.ChunkBy = "date"
+		m.Window = f.Window
+		m.Chunks, err = dateChunks(params.Get(d.DateParams[0]), params.Get(d.DateParams[1]), f.Window)
+		if err != nil {
+			return err
+		}
+	case !oldest.IsZero():
+		m.ChunkBy = "modified"
+		m.Window = f.Window
+		m.Query.Set("sort", "modified")
+		m.Query.Set("sortorder", "asc")
+		m.Chunks, err = modifiedChunks(oldest, time.Now(), f.Window)
+		if err != nil {
+			return err
+		}
+	default:
+		// Pages keep the requested order, and serve when there is nothing
+		// (or no readable modification time) to split by.
+		m.ChunkBy = "page"
+		probe := opts
+		probe.Size = 1
+		var attempts int
+		result, err := listWithRetry(ctx, client, d.Endpoint, probe, params, &attempts)
+		if err != nil {
+			return err
+		}
+		m.Hits = result.Hits
+		for page := 0; page*f.ChunkSize < result.Hits; page++ {
+			page := page
+			m.Chunks = append(m.Chunks, exportChunk{Index: len(m.Chunks), Page: &page, Status: "pending"})
+		}
+	}
+
+	fetchChunks(ctx, client, d, opts, params, m.ChunkBy, m.Chunks, f.Concurrency)
+
+	var failed, done int
+	for _, c := range m.Chunks {
+		switch c.Status {
+		case "ok":
+			done++
+		case "failed":
+			failed++
+		}
+	}
+
+	if ctx.Err() != nil {
+		if err := writeManifest(manifestPath, m); err != nil {
+			return err
+		}
+		return stopped(ctx, "after %d of %d chunks; %s was not written, see %s", done, len(m.Chunks), file, manifestPath)
+	}
+	if done == 0 && failed > 0 {
+		if err := writeManifest(manifestPath, m); err != nil {
+			return err
+		}
+		return fmt.Errorf("all %d chunks failed, %s was not written: %s", failed, file, m.Chunks[0].Error)
+	}
+
+	// Merge in chunk order. Resources that are in several windows, such as
+	// events with dates in several date windows, are kept at their first
+	// occurrence.
+	var raws []json.RawMessage
+	seen := make(map[string]bool)
+	for _, c := range m.Chunks {
+		resources, err := feedfactory.ParseResources(c.results)
+		if err != nil {
+			return err
+		}
+		for i, r := range resources {
+			if r.ID != "" && seen[r.ID] {
+				m.Duplicates++
+				continue
+			}
+			seen[r.ID] = true
+			raws = append(raws, c.results[i])
+		}
+	}
+
+	err = writeAtomic(file, func(w io.Writer) error {
+		return writeChunkedExport(w, format, d, raws)
+	})
+	if err != nil {
+		return err
+	}
+	m.Written = len(raws)
+	m.Complete = failed == 0
+	if err := writeManifest(manifestPath, m); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d chunks failed; %s is incomplete, see %s", failed, len(m.Chunks), file, manifestPath)
+	}
+	fmt.Printf("Exported %d %s to %s in %d chunks (manifest: %s)\n", len(raws), d.Plural, file, len(m.Chunks), manifestPath)
+	return nil
+}
+
+// chunkedFormat returns the output format of a chunked export to file.
+func chunkedFormat(file string) (string, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xlsx":
+		return "xlsx", nil
+	case ".csv":
+		return "csv", nil
+	case ".ndjson", ".jsonl":
+		return "ndjson", nil
+	}
+	return "", fmt.Errorf("--chunked writes .xlsx, .csv or .ndjson files, not %q", filepath.Base(file))
+}
+
+// windowStep parses a --window length and returns a function that moves a
+// time on by one window.
+func windowStep(window string) (func(time.Time) time.Time, error) {
+	m := offsetRe.FindStringSubmatch(window)
+	n := 0
+	if m != nil && m[1] == "" {
+		n, _ = strconv.Atoi(m[2])
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("--window must be a length such as 7d, 2w, 1mo or 1y, not %q", window)
+	}
+	return func(t time.Time) time.Time {
+		switch m[3] {
+		case "w":
+			return t.AddDate(0, 0, 7*n)
+		case "mo":
+			return t.AddDate(0, n, 0)
+		case "y":
+			return t.AddDate(n, 0, 0)
+		}
+		return t.AddDate(0, 0, n)
+	}, nil
+}
+
+// dateChunks splits the days from first to last (yyyy-mm-dd, inclusive) into
+// windows of the given length.
+func dateChunks(first, last, window string) ([]exportChunk, error) {
+	next, err := windowStep(window)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", first, apiLocation)
+	if err != nil {
+		return nil, fmt.Errorf("date range start %q: %w", first, err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", last, apiLocation)
+	if err != nil {
+		return nil, fmt.Errorf("date range end %q: %w", last, err)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("date range end %s is before its start %s", last, first)
+	}
+
+	var chunks []exportChunk
+	for start := from; !start.After(to); start = next(start) {
+		end := next(start).AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		chunks = append(chunks, exportChunk{
+			Index:  len(chunks),
+			From:   start.Format("2006-01-02"),
+			To:     end.Format("2006-01-02"),
+			Status: "pending",
+		})
+	}
+	return chunks, nil
+}
+
+// oldestModification returns the last modification time of the least recently
+// modified resource matching opts and params. It is zero when nothing matches
+// or the time can't be read, and the export is then split into pages.
+func oldestModification(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, opts feedfactory.ListOptions, params url.Values) (time.Time, error) {
+	opts.Sort, opts.Asc = "modified", true
+	opts.Size, opts.Page = 1, 0
+	var attempts int
+	result, err := listWithRetry(ctx, client, d.Endpoint, opts, params, &attempts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resources, err := feedfactory.ParseResources(result.Results)
+	if err != nil || len(resources) == 0 {
+		return time.Time{}, err
+	}
+	return resources[0].LastUpdated.Time, nil
+}
+
+// modifiedChunks splits the time from oldest to now into windows of the given
+// length. The last window has no end, so resources modified while the export
+// runs are still included.
+func modifiedChunks(oldest, now time.Time, window string) ([]exportChunk, error) {
+	next, err := windowStep(window)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []exportChunk
+	for start := oldest.In(apiLocation); ; start = next(start) {
+		c := exportChunk{Index: len(chunks), From: start.Format(time.RFC3339), Status: "pending"}
+		if end := next(start); !end.After(now) {
+			c.To = end.Format(time.RFC3339)
+		}
+		chunks = append(chunks, c)
+		if c.To == "" {
+			return chunks, nil
+		}
+	}
+}
+
+// fetchChunks fetches the chunks with up to concurrency requests at a time,
+// filling in their results and status. When ctx is done, chunks that were not
+// finished are marked cancelled.
+func fetchChunks(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, opts feedfactory.ListOptions, params url.Values, by string, chunks []exportChunk, concurrency int) {
+	jobs := make(chan *exportChunk)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	finished, fetched := 0, 0
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				err := fetchChunk(ctx, client, d, opts, params, by, c)
+				switch {
+				case ctx.Err() != nil:
+					c.Status = "cancelled"
+					c.results = nil
+				case err != nil:
+					c.Status = "failed"
+					c.Error = err.Error()
+					c.results = nil
+				default:
+					c.Status = "ok"
+					c.Count = len(c.results)
+				}
+
+				mu.Lock()
+				finished++
+				fetched += len(c.results)
+				fmt.Fprintf(os.Stderr, "Fetched %d of %d chunks (%d %s)\r", finished, len(chunks), fetched, d.Plural)
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range chunks {
+		select {
+		case jobs <- &chunks[i]:
+		case <-ctx.Done():
+			for j := i; j < len(chunks); j++ {
+				chunks[j].Status = "cancelled"
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	fmt.Fprintln(os.Stderr)
+}
+
+// fetchChunk fetches all resources of a chunk: one page of a page chunk, or all
+// pages of a date or modification window.
+func fetchChunk(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, opts feedfactory.ListOptions, params url.Values, by string, c *exportChunk) error {
+	if by == "modified" {
+		return fetchModifiedWindow(ctx, client, d, opts, params, c)
+	}
+	if c.Page != nil {
+		opts.Page = *c.Page
+		result, err := listWithRetry(ctx, client, d.Endpoint, opts, params, &c.Attempts)
+		if err != nil {
+			return err
+		}
+		c.results = result.Results
+		return nil
+	}
+
+	windowParams := url.Values{}
+	for k, v := range params {
+		windowParams[k] = v
+	}
+	windowParams.Set(d.DateParams[0], c.From)
+	windowParams.Set(d.DateParams[1], c.To)
+	for page := 0; ; page++ {
+		opts.Page = page
+		result, err := listWithRetry(ctx, client, d.Endpoint, opts, windowParams, &c.Attempts)
+		if err != nil {
+			return err
+		}
+		c.results = append(c.results, result.Results...)
+		if len(result.Results) == 0 || len(c.results) >= result.Hits {
+			return nil
+		}
+	}
+}
+
+// fetchModifiedWindow fetches the resources last modified in the window of c.
+// The API only filters on a start, so the window is read oldest first until a
+// resource was modified after its end.
+func fetchModifiedWindow(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, opts feedfactory.ListOptions, params url.Values, c *exportChunk) error {
+	from, err := time.Parse(time.RFC3339, c.From)
+	if err != nil {
+		return err
+	}
+	var to time.Time
+	if c.To != "" {
+		if to, err = time.Parse(time.RFC3339, c.To); err != nil {
+			return err
+		}
+	}
+	inWindow := func(t time.Time) bool {
+		return !t.Before(from) && (to.IsZero() || t.Before(to))
+	}
+
+	// A second early, in case the API filter excludes its start.
+	opts.UpdatedSince = from.Add(-time.Second).Format(time.RFC3339)
+	opts.Sort, opts.Asc = "modified", true
+	read := 0
+	for page := 0; ; page++ {
+		opts.Page = page
+		result, err := listWithRetry(ctx, client, d.Endpoint, opts, params, &c.Attempts)
+		if err != nil {
+			return err
+		}
+		resources, err := feedfactory.ParseResources(result.Results)
+		if err != nil {
+			return err
+		}
+		for i, r := range resources {
+			// Resources without a readable time are kept in every window
+			// and written once by the merge.
+			if t := r.LastUpdated.Time; t.IsZero() || inWindow(t) {
+				c.results = append(c.results, result.Results[i])
+			}
+		}
+		read += len(resources)
+		if len(resources) == 0 || read >= result.Hits {
+			return nil
+		}
+		if last := resources[len(resources)-1].LastUpdated.Time; !to.IsZero() && !last.Before(to) {
+			return nil
+		}
+	}
+}
+
+// listWithRetry calls ListResources, retrying server errors, rate limiting and
+// network failures with a growing delay. attempts counts the requests made.
+func listWithRetry(ctx context.Context, client *feedfactory.Client, endpoint string, opts feedfactory.ListOptions, params url.Values, attempts *int) (*feedfactory.SearchResult, error) {
+	for try := 1; ; try++ {
+		*attempts++
+		result, err := client.ListResources(ctx, endpoint, opts, params)
+		if err == nil || try == chunkAttempts || ctx.Err() != nil || !retryable(err) {
+			return result, err
+		}
+		select {
+		case <-time.After(time.Duration(try) * 2 * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryable reports whether a failed request may succeed when tried again.
+func retryable(err error) bool {
+	var apiErr *feedfactory.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// writeChunkedExport writes the merged resources of a chunked export to w.
+func writeChunkedExport(w io.Writer, format string, d resourceDescriptor, raws []json.RawMessage) error {
+	if format == "ndjson" {
+		var buf bytes.Buffer
+		for _, raw := range raws {
+			buf.Reset()
+			if err := json.Compact(&buf, raw); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cols, err := view[resourceRow]{Columns: d.Columns}.selectColumns("csv")
+	if err != nil {
+		return err
+	}
+	resources, err := feedfactory.ParseResources(raws)
+	if err != nil {
+		return err
+	}
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	records := make([][]string, len(resources))
+	for i, r := range resources {
+		row := resourceRow{Resource: r, raw: raws[i]}
+		records[i] = make([]string, len(cols))
+		for j, c := range cols {
+			records[i][j] = c.Value(row)
+		}
+	}
+
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		cw.Write(header)
+		cw.WriteAll(records)
+		return cw.Error()
+	}
+
+	rows := make([][]interface{}, len(records))
+	for i, record := range records {
+		rows[i] = make([]interface{}, len(record))
+		for j, v := range record {
+			rows[i][j] = v
+		}
+	}
+	columns := make([]xlsxColumn, len(header))
+	for i, name := range header {
+		columns[i] = xlsxColumn{Name: name}
+	}
+	return writeWorkbook(w, []xlsxSheet{{Name: d.Plural, Columns: columns, Rows: rows}})
+}
+
+// writeManifest writes the manifest of a chunked export as indented JSON.
+func writeManifest(path string, m *exportManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	})
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// fakeList serves a list endpoint over docs: it filters on lastupdated, sorts
+// by modified or created and pages like the API. It records the queries.
+type fakeList struct {
+	docs []map[string]string
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (f *fakeList) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f.mu.Lock()
+	f.queries = append(f.queries, q.Encode())
+	f.mu.Unlock()
+
+	var docs []map[string]string
+	since, _ := time.Parse(time.RFC3339, q.Get("lastupdated"))
+	for _, d := range f.docs {
+		t, _ := time.Parse(time.RFC3339, d["lastupdated"])
+		if q.Get("lastupdated") == "" || !t.Before(since) {
+			docs = append(docs, d)
+		}
+	}
+	key := map[string]string{"modified": "lastupdated", "created": "creationdate"}[q.Get("sort")]
+	sort.SliceStable(docs, func(i, j int) bool {
+		less := docs[i][key] < docs[j][key]
+		if q.Get("sortorder") != "asc" {
+			less = docs[i][key] > docs[j][key]
+		}
+		return less
+	})
+	size, _ := strconv.Atoi(q.Get("size"))
+	page, _ := strconv.Atoi(q.Get("page"))
+	from := min(page*size, len(docs))
+	to := min(from+size, len(docs))
+	json.NewEncoder(w).Encode(map[string]interface{}{"hits": len(docs), "page": page, "size": size, "results": docs[from:to]})
+}
+
+func TestChunkedExportByModification(t *testing.T) {
+	// 30 locations, one modified every ten days from 1 January 2026, created
+	// in the reverse order.
+	list := &fakeList{}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		list.docs = append(list.docs, map[string]string{
+			"id":           fmt.Sprintf("L%02d", i),
+			"lastupdated":  start.AddDate(0, 0, 10*i).Format(time.RFC3339),
+			"creationdate": start.AddDate(0, 0, -i).Format(time.RFC3339),
+		})
+	}
+	srv := httptest.NewServer(list)
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	file := filepath.Join(t.TempDir(), "locations.ndjson")
+	f := &chunkFlags{Window: "1mo", ChunkSize: 2, Concurrency: 3}
+	if err := f.export(context.Background(), client, describe[locationKind](), file, feedfactory.ListOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 30 {
+		t.Fatalf("wrote %d locations, want 30", len(lines))
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf(`"id":"L%02d"`, i); !strings.Contains(line, want) {
+			t.Errorf("line %d is %s, want %s: the file should be ordered by modification", i, line, want)
+		}
+	}
+
+	var m exportManifest
+	raw, _ := os.ReadFile(file + ".manifest.json")
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ChunkBy != "modified" || !m.Complete || m.Written != 30 {
+		t.Errorf("manifest: chunked by %q, complete %v, written %d", m.ChunkBy, m.Complete, m.Written)
+	}
+	first, last := m.Chunks[0], m.Chunks[len(m.Chunks)-1]
+	if !strings.HasPrefix(first.From, "2026-01-01T13:00:00") || last.To != "" {
+		t.Errorf("windows run from %s to %q, want from the oldest modification to no end", first.From, last.To)
+	}
+	total := 0
+	for _, c := range m.Chunks {
+		total += c.Count
+		if c.Page != nil {
+			t.Errorf("chunk %d is a page", c.Index)
+		}
+	}
+	if total != 30 {
+		t.Errorf("chunks hold %d locations, want 30 with no overlap", total)
+	}
+}
+
+func TestChunkedExportWithSortUsesPages(t *testing.T) {
+	list := &fakeList{}
+	for i := 0; i < 5; i++ {
+		list.docs = append(list.docs, map[string]string{"id": fmt.Sprintf("L%d", i), "lastupdated": "2026-01-01T00:00:00Z", "creationdate": fmt.Sprintf("2025-01-0%dT00:00:00Z", i+1)})
+	}
+	srv := httptest.NewServer(list)
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	file := filepath.Join(t.TempDir(), "locations.ndjson")
+	f := &chunkFlags{Window: "1mo", ChunkSize: 2, Concurrency: 1}
+	opts := feedfactory.ListOptions{Sort: "created"}
+	if err := f.export(context.Background(), client, describe[locationKind](), file, opts, nil); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(file + ".manifest.json")
+	var m exportManifest
+	json.Unmarshal(raw, &m)
+	if m.ChunkBy != "page" || len(m.Chunks) != 3 || m.Written != 5 {
+		t.Errorf("chunked by %q into %d chunks, wrote %d; want 3 pages and 5 locations", m.ChunkBy, len(m.Chunks), m.Written)
+	}
+}
+
+func TestModifiedChunks(t *testing.T) {
+	oldest := time.Date(2026, 1, 31, 10, 0, 0, 0, apiLocation)
+	now := time.Date(2026, 4, 15, 0, 0, 0, 0, apiLocation)
+	chunks, err := modifiedChunks(oldest, now, "1mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.From+" "+c.To)
+	}
+	want := []string{
+		"2026-01-31T10:00:00+01:00 2026-03-03T10:00:00+01:00",
+		"2026-03-03T10:00:00+01:00 2026-04-03T10:00:00+02:00",
+		"2026-04-03T10:00:00+02:00 ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("windows:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if _, err := modifiedChunks(oldest, now, "-1mo"); err == nil {
+		t.Error("expected an error for a negative window")
+	}
+}
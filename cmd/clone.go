@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type ResourceCloneCmd[K resourceKind] struct {
+	ID         string   `arg:"" help:"ID of the ${singular} to copy."`
+	Set        []string `placeholder:"PATH=VALUE" sep:"none" help:"Set a field of the copy, e.g. --set trcItemDetails[lang=nl].title='Zomerfestival 2027'. Paths are as in import mappings. Values that are valid JSON (numbers, true, false, quoted strings, arrays, objects) are set as such, anything else as text. Repeat for several fields."`
+	ShiftDates string   `name:"shift-dates" help:"Move all dates of the calendar by an offset, e.g. 1y, 52w (keeps the weekdays), 3mo or -7d. Unsigned offsets move forward."`
+	DryRun     bool     `name:"dry-run" help:"Print the document that would be created without creating it."`
+}
+
+// cloneStripped are the fields of the original that the copy doesn't get: the
+// ones the server manages, and externalid, which must stay unique for imports
+// to find the original.
+var cloneStripped = []string{"id", "slug", "trcid", "creationdate", "lastupdated", "lastupdatedby", "wfstatus", "published", "externalid"}
+
+func (c *ResourceCloneCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	overrides, err := parseSetFlags(c.Set)
+	if err != nil {
+		return err
+	}
+
+	raw, err := client.GetResource(ctx, d.Endpoint, c.ID)
+	if err != nil {
+		return err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("parsing %s %s: %w", d.Singular, c.ID, err)
+	}
+
+	for _, field := range cloneStripped {
+		delete(doc, docKey(doc, field))
+	}
+	doc["wfstatus"] = "draft"
+	if c.ShiftDates != "" {
+		n, err := shiftDates(doc, c.ShiftDates)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("%s %s has no dates to shift", d.Singular, c.ID)
+		}
+	}
+	for _, o := range overrides {
+		if err := o.path.set(doc, o.value); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if c.DryRun {
+		return printRawJSON(data)
+	}
+	created, err := client.CreateResource(ctx, d.Endpoint, data)
+	if err != nil {
+		return fmt.Errorf("creating copy of %s %s: %w", d.Singular, c.ID, err)
+	}
+	id, err := createdID(created)
+	if err != nil {
+		return fmt.Errorf("creating copy of %s %s: %w", d.Singular, c.ID, err)
+	}
+	fmt.Printf("Created %s %s as a draft copy of %s.\n", d.Singular, id, c.ID)
+	return nil
+}
+
+// docOverride is a parsed --set flag.
+type docOverride struct {
+	path  docPath
+	value interface{}
+}
+
+// parseSetFlags parses path=value flags. Values that are valid JSON are
+// decoded; anything else is text.
+func parseSetFlags(flags []string) ([]docOverride, error) {
+	var out []docOverride
+	for _, f := range flags {
+		// The first = outside a [key=value] selector ends the path.
+		eq, depth := -1, 0
+		for i := 0; i < len(f) && eq < 0; i++ {
+			switch f[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			case '=':
+				if depth == 0 {
+					eq = i
+				}
+			}
+		}
+		if eq < 0 {
+			return nil, fmt.Errorf("--set %q: use path=value", f)
+		}
+		p, v := f[:eq], f[eq+1:]
+		path, err := parseDocPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("--set %q: %w", f, err)
+		}
+		var value interface{} = v
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(v), &decoded); err == nil {
+			value = decoded
+		}
+		out = append(out, docOverride{path: path, value: value})
+	}
+	return out, nil
+}
+
+// shiftDates moves the single dates and pattern date bounds in the calendar of
+// doc by an offset such as 1y or -7d, and returns how many dates it moved.
+func shiftDates(doc map[string]interface{}, offset string) (int, error) {
+	m := offsetRe.FindStringSubmatch(strings.TrimSpace(offset))
+	if m == nil {
+		return 0, fmt.Errorf("invalid --shift-dates %q (use e.g. 1y, 52w, 3mo or -7d)", offset)
+	}
+	n, _ := strconv.Atoi(m[2])
+	if m[1] == "-" {
+		n = -n
+	}
+	shift := func(t time.Time) time.Time {
+		switch m[3] {
+		case "d":
+			return t.AddDate(0, 0, n)
+		case "w":
+			return t.AddDate(0, 0, 7*n)
+		case "mo":
+			return t.AddDate(0, n, 0)
+		}
+		return t.AddDate(n, 0, 0)
+	}
+
+	cal, _ := doc[docKey(doc, "calendar")].(map[string]interface{})
+	if cal == nil {
+		return 0, nil
+	}
+	moved := 0
+	fields := map[string][]string{
+		"singleDates":  {"date"},
+		"patternDates": {"startdate", "enddate"},
+	}
+	for list, keys := range fields {
+		items, _ := cal[docKey(cal, list)].([]interface{})
+		for _, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range keys {
+				k := docKey(obj, key)
+				s, _ := obj[k].(string)
+				if len(s) < len(dateLayout) {
+					continue
+				}
+				// Keep anything after the date, such as a time.
+				t, err := time.Parse(dateLayout, s[:len(dateLayout)])
+				if err != nil {
+					return moved, fmt.Errorf("calendar.%s: invalid date %q", list, s)
+				}
+				obj[k] = shift(t).Format(dateLayout) + s[len(dateLayout):]
+				moved++
+			}
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/TheFeedFactory/tff-cli/internal/dedupe"
+)
+
+type DedupeCmd struct {
+	Type      string  `arg:"" enum:"locations,venues,events" help:"Resource type to check for duplicates: locations, venues or events."`
+	Threshold float64 `default:"0.75" help:"Minimum confidence (0-1) for two resources to be reported as duplicates. Default: 0.75."`
+	Plan      string  `type:"path" help:"Write a merge plan (JSON) to this file, proposing which resource of each group to keep."`
+	JSON      bool    `short:"j" help:"Output duplicate groups as JSON."`
+	ScanFlags
+}
+
+func (c *DedupeCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	if c.Threshold <= 0 || c.Threshold > 1 {
+		return fmt.Errorf("--threshold must be between 0 and 1")
+	}
+
+	resources, err := c.scan(ctx, client, c.Type)
+	if err != nil {
+		return err
+	}
+
+	groups := dedupe.Find(resources, dedupe.Options{
+		Threshold: c.Threshold,
+		Events:    c.Type == "events",
+	})
+
+	if c.Plan != "" {
+		data, err := json.MarshalIndent(dedupe.NewPlan(c.Type, groups), "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding merge plan: %w", err)
+		}
+		if err := os.WriteFile(c.Plan, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("writing merge plan: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote merge plan for %d groups to %s\n", len(groups), c.Plan)
+	}
+
+	if jsonOutput(c.JSON) {
+		if groups == nil {
+			groups = []dedupe.Group{}
+		}
+		return printJSON(groups)
+	}
+
+	if len(groups) == 0 {
+		fmt.Printf("No duplicates found among %d %s.\n", len(resources), c.Type)
+		return nil
+	}
+
+	for i, g := range groups {
+		fmt.Printf("Group %d (confidence %.2f)\n", i+1, g.Confidence)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  ID\tTITLE\tADDRESS\tORGANISATION\tSTATUS\tPUBLISHED")
+		for _, m := range g.Members {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+				m.ID, truncate(m.Title, 40), truncate(m.Address, 40), truncate(m.UserOrg, 20), m.WFStatus, boolYesNo(m.Published))
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	fmt.Printf("%d duplicate groups among %d %s\n", len(groups), len(resources), c.Type)
+	return nil
+}
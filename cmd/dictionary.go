@@ -1,12 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"text/tabwriter"
 
-	"github.com/TheFeedFactory/tff-cli/internal/api"
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
 )
 
 type DictionaryCmd struct {
@@ -21,13 +19,13 @@ type DictionaryKeywordsCmd struct {
 	JSON bool   `short:"j" help:"Output as JSON."`
 }
 
-func (c *DictionaryKeywordsCmd) Run(client *api.Client) error {
-	data, err := client.GetKeywords(c.Type)
+func (c *DictionaryKeywordsCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	data, err := client.GetKeywords(ctx, c.Type)
 	if err != nil {
 		return err
 	}
 
-	return printRawJSON(data)
+	return renderGeneric(data, c.JSON, "No keywords found.")
 }
 
 type DictionaryMarkersCmd struct {
@@ -35,66 +33,82 @@ type DictionaryMarkersCmd struct {
 	JSON bool   `short:"j" help:"Output as JSON."`
 }
 
-func (c *DictionaryMarkersCmd) Run(client *api.Client) error {
-	data, err := client.GetMarkers(c.Type)
+func (c *DictionaryMarkersCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	data, err := client.GetMarkers(ctx, c.Type)
 	if err != nil {
 		return err
 	}
 
-	return printRawJSON(data)
+	return renderGeneric(data, c.JSON, "No markers found.")
 }
 
 type DictionaryOntologyCmd struct {
 	JSON bool `short:"j" help:"Output as JSON."`
 }
 
-func (c *DictionaryOntologyCmd) Run(client *api.Client) error {
-	data, err := client.GetOntology()
+func (c *DictionaryOntologyCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	data, err := client.GetOntology(ctx)
 	if err != nil {
 		return err
 	}
 
-	if c.JSON {
-		return printRawJSON(data)
-	}
-
 	// Parse and display as a tree
-	var ontology struct {
-		LastModified    string           `json:"lastModified"`
-		Categorizations []Categorization `json:"categorizations"`
-	}
-	if err := json.Unmarshal(data, &ontology); err != nil {
+	ontology, err := feedfactory.ParseOntology(data)
+	if err != nil {
 		// Fall back to raw JSON on parse error
 		return printRawJSON(data)
 	}
 
-	if ontology.LastModified != "" {
-		fmt.Printf("Last modified: %s\n\n", ontology.LastModified)
+	// Flat rows for csv, ids and ndjson output
+	type ontologyRow struct {
+		ID         string `json:"id"`
+		CnetID     string `json:"cnetid"`
+		Name       string `json:"name"`
+		EntityType string `json:"entitytype,omitempty"`
+		Parent     string `json:"parent,omitempty"`
+		Deprecated bool   `json:"deprecated"`
 	}
-
-	for _, cat := range ontology.Categorizations {
-		printCategory(cat, 0)
+	var rows []ontologyRow
+	var collect func(cats []feedfactory.Categorization, parent, entityType string)
+	collect = func(cats []feedfactory.Categorization, parent, entityType string) {
+		for _, cat := range cats {
+			if cat.EntityType != "" {
+				entityType = cat.EntityType
+			}
+			row := ontologyRow{CnetID: cat.CnetID, Name: cat.Name, EntityType: entityType, Parent: parent, Deprecated: cat.IsDeprecated()}
+			if cat.ID != nil {
+				row.ID = *cat.ID
+			}
+			rows = append(rows, row)
+			collect(cat.Children, cat.CnetID, entityType)
+		}
 	}
-
-	return nil
-}
-
-type Categorization struct {
-	CnetID       string           `json:"cnetID"`
-	Name         string           `json:"categorization"`
-	ID           *string          `json:"categorizationId"`
-	EntityType   string           `json:"entityType"`
-	Deprecated   interface{}      `json:"deprecated"`
-	Children     []Categorization `json:"child"`
-	Translations []CatTranslation `json:"categoryTranslations"`
-}
-
-type CatTranslation struct {
-	Lang  string `json:"lang"`
-	Label string `json:"label"`
+	collect(ontology.Categorizations, "", "")
+
+	return view[ontologyRow]{
+		Columns: []column[ontologyRow]{
+			{Name: "id", Value: func(r ontologyRow) string { return r.ID }},
+			{Name: "cnetid", Value: func(r ontologyRow) string { return r.CnetID }},
+			{Name: "name", Width: 50, Value: func(r ontologyRow) string { return r.Name }},
+			{Name: "entitytype", Value: func(r ontologyRow) string { return r.EntityType }},
+			{Name: "parent", Value: func(r ontologyRow) string { return r.Parent }},
+			{Name: "deprecated", Value: func(r ontologyRow) string { return boolYesNo(r.Deprecated) }},
+		},
+		Rows:     rows,
+		Document: rawDoc(data),
+		Text: func() error {
+			if ontology.LastModified != "" {
+				fmt.Printf("Last modified: %s\n\n", ontology.LastModified)
+			}
+			for _, cat := range ontology.Categorizations {
+				printCategory(cat, 0)
+			}
+			return nil
+		},
+	}.render(c.JSON)
 }
 
-func printCategory(cat Categorization, depth int) {
+func printCategory(cat feedfactory.Categorization, depth int) {
 	indent := ""
 	for i := 0; i < depth; i++ {
 		indent += "  "
@@ -106,12 +120,8 @@ func printCategory(cat Categorization, depth int) {
 	}
 
 	deprecated := ""
-	if cat.Deprecated != nil && cat.Deprecated != false {
-		if s, ok := cat.Deprecated.(string); ok && s != "" {
-			deprecated = " [DEPRECATED]"
-		} else if b, ok := cat.Deprecated.(bool); ok && b {
-			deprecated = " [DEPRECATED]"
-		}
+	if cat.IsDeprecated() {
+		deprecated = " [DEPRECATED]"
 	}
 
 	fmt.Printf("%s%s  %s%s%s\n", indent, cat.CnetID, cat.Name, idStr, deprecated)
@@ -127,32 +137,23 @@ type DictionaryCategoriesCmd struct {
 	Type string `name:"type" short:"t" default:"" help:"Filter by entity type: event, location, route, eventgroup." enum:",event,location,route,eventgroup"`
 }
 
-func (c *DictionaryCategoriesCmd) Run(client *api.Client) error {
-	data, err := client.GetOntology()
+func (c *DictionaryCategoriesCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	data, err := client.GetOntology(ctx)
 	if err != nil {
 		return err
 	}
 
-	var ontology struct {
-		Categorizations []Categorization `json:"categorizations"`
-	}
-	if err := json.Unmarshal(data, &ontology); err != nil {
+	ontology, err := feedfactory.ParseOntology(data)
+	if err != nil {
 		return printRawJSON(data)
 	}
 
-	// Map user-friendly type names to ontology entityType values
-	entityTypeMap := map[string]string{
-		"event":      "EVENEMENT",
-		"location":   "LOCATIE",
-		"route":      "ROUTE",
-		"eventgroup": "EVENEMENTGROEP",
-	}
-	filterEntityType := entityTypeMap[c.Type]
+	filterEntityType := feedfactory.OntologyEntityType(c.Type)
 
 	// Filter top-level categorizations by entity type if specified
 	topCats := ontology.Categorizations
 	if filterEntityType != "" {
-		var filtered []Categorization
+		var filtered []feedfactory.Categorization
 		for _, cat := range topCats {
 			if cat.EntityType == filterEntityType {
 				filtered = append(filtered, cat)
@@ -169,8 +170,8 @@ func (c *DictionaryCategoriesCmd) Run(client *api.Client) error {
 	}
 
 	var categories []flatCat
-	var collect func(cats []Categorization, parent string)
-	collect = func(cats []Categorization, parent string) {
+	var collect func(cats []feedfactory.Categorization, parent string)
+	collect = func(cats []feedfactory.Categorization, parent string) {
 		for _, cat := range cats {
 			label := cat.Name
 			for _, t := range cat.Translations {
@@ -198,24 +199,17 @@ func (c *DictionaryCategoriesCmd) Run(client *api.Client) error {
 	}
 	collect(topCats, "")
 
-	if c.JSON {
-		return printJSON(categories)
-	}
-
-	if len(categories) == 0 {
-		fmt.Println("No categories found.")
-		return nil
-	}
-
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tLABEL\tPARENT")
-	fmt.Fprintln(w, "--\t-----\t------")
-
-	for _, cat := range categories {
-		fmt.Fprintf(w, "%s\t%s\t%s\n", cat.ID, truncate(cat.Label, 40), truncate(cat.Parent, 30))
-	}
-	w.Flush()
-
-	fmt.Printf("\nTotal: %d categories\n", len(categories))
-	return nil
+	if categories == nil {
+		categories = []flatCat{}
+	}
+	return view[flatCat]{
+		Columns: []column[flatCat]{
+			{Name: "id", Value: func(c flatCat) string { return c.ID }},
+			{Name: "label", Width: 40, Value: func(c flatCat) string { return c.Label }},
+			{Name: "parent", Width: 30, Value: func(c flatCat) string { return c.Parent }},
+		},
+		Rows:   categories,
+		Empty:  "No categories found.",
+		Footer: fmt.Sprintf("Total: %d categories", len(categories)),
+	}.render(c.JSON)
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// docPath is a parsed path into a raw resource document, such as
+// location.address.city, calendar.singleDates[0].date or
+// trcItemDetails[lang=nl].title. A step [n] selects an array element by
+// position; [key=value] selects the element whose key field equals value.
+// Field names match existing keys case-insensitively.
+type docPath []pathStep
+
+type pathStep struct {
+	key string
+	// array steps select an element of the array in key, by index or by match.
+	array      bool
+	index      int
+	matchKey   string
+	matchValue string
+}
+
+func parseDocPath(s string) (docPath, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	var p docPath
+	for _, part := range strings.Split(s, ".") {
+		step := pathStep{key: part}
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("path %q: missing ] in %q", s, part)
+			}
+			step.key = part[:open]
+			step.array = true
+			sel := part[open+1 : len(part)-1]
+			if k, v, ok := strings.Cut(sel, "="); ok {
+				if k == "" {
+					return nil, fmt.Errorf("path %q: empty field name in [%s]", s, sel)
+				}
+				step.matchKey, step.matchValue = k, v
+			} else {
+				n, err := strconv.Atoi(sel)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("path %q: [%s] must be an index or key=value", s, sel)
+				}
+				step.index = n
+			}
+		}
+		if step.key == "" {
+			return nil, fmt.Errorf("path %q: empty field name", s)
+		}
+		p = append(p, step)
+	}
+	if p[len(p)-1].array {
+		return nil, fmt.Errorf("path %q must end in a field name", s)
+	}
+	return p, nil
+}
+
+func (p docPath) String() string {
+	parts := make([]string, len(p))
+	for i, step := range p {
+		parts[i] = step.key
+		switch {
+		case step.array && step.matchKey != "":
+			parts[i] += "[" + step.matchKey + "=" + step.matchValue + "]"
+		case step.array:
+			parts[i] += "[" + strconv.Itoa(step.index) + "]"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// get returns the value at the path, if there is one.
+func (p docPath) get(doc map[string]interface{}) (interface{}, bool) {
+	obj := doc
+	for i, step := range p {
+		v, ok := obj[docKey(obj, step.key)]
+		if !ok {
+			return nil, false
+		}
+		if step.array {
+			arr, _ := v.([]interface{})
+			if v = step.find(arr); v == nil {
+				return nil, false
+			}
+		}
+		if i == len(p)-1 {
+			return v, true
+		}
+		if obj, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// set stores value at the path, creating the objects and array elements on the
+// way. An element selected by [key=value] is created with that field.
+func (p docPath) set(doc map[string]interface{}, value interface{}) error {
+	obj := doc
+	for i, step := range p {
+		key := docKey(obj, step.key)
+		if i == len(p)-1 {
+			obj[key] = value
+			return nil
+		}
+
+		if !step.array {
+			next, ok := obj[key].(map[string]interface{})
+			if !ok {
+				if obj[key] != nil {
+					return fmt.Errorf("%s: %s is not an object", p, step.key)
+				}
+				next = map[string]interface{}{}
+				obj[key] = next
+			}
+			obj = next
+			continue
+		}
+
+		arr, ok := obj[key].([]interface{})
+		if !ok && obj[key] != nil {
+			return fmt.Errorf("%s: %s is not an array", p, step.key)
+		}
+		elem := step.find(arr)
+		if elem == nil {
+			if step.matchKey != "" {
+				elem = map[string]interface{}{step.matchKey: step.matchValue}
+				arr = append(arr, elem)
+			} else {
+				for len(arr) <= step.index {
+					arr = append(arr, map[string]interface{}{})
+				}
+				if arr[step.index] == nil {
+					arr[step.index] = map[string]interface{}{}
+				}
+				elem = arr[step.index]
+			}
+			obj[key] = arr
+		}
+		next, ok := elem.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: element of %s is not an object", p, step.key)
+		}
+		obj = next
+	}
+	return nil
+}
+
+// find returns the array element the step selects, or nil.
+func (step pathStep) find(arr []interface{}) interface{} {
+	if step.matchKey == "" {
+		if step.index < len(arr) {
+			return arr[step.index]
+		}
+		return nil
+	}
+	for _, elem := range arr {
+		if m, ok := elem.(map[string]interface{}); ok {
+			if v, ok := m[docKey(m, step.matchKey)]; ok && fmt.Sprint(v) == step.matchValue {
+				return elem
+			}
+		}
+	}
+	return nil
+}
+
+// docKey returns the key of obj that matches name case-insensitively, or name
+// itself when obj has no such key.
+func docKey(obj map[string]interface{}, name string) string {
+	if _, ok := obj[name]; ok {
+		return name
+	}
+	for k := range obj {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestParseDocPath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want docPath
+		err  string
+	}{
+		{in: "title", want: docPath{{key: "title"}}},
+		{in: " location.address.city ", want: docPath{{key: "location"}, {key: "address"}, {key: "city"}}},
+		{in: "calendar.singleDates[0].date", want: docPath{{key: "calendar"}, {key: "singleDates", array: true}, {key: "date"}}},
+		{in: "trcItemDetails[lang=nl].title", want: docPath{{key: "trcItemDetails", array: true, matchKey: "lang", matchValue: "nl"}, {key: "title"}}},
+		{in: "urls[urltype=].url", want: docPath{{key: "urls", array: true, matchKey: "urltype"}, {key: "url"}}},
+		{in: "", err: "empty path"},
+		{in: "location..city", err: "empty field name"},
+		{in: "[0].date", err: "empty field name"},
+		{in: "singleDates[0.date", err: "missing ]"},
+		{in: "singleDates[x].date", err: "must be an index or key=value"},
+		{in: "singleDates[-1].date", err: "must be an index or key=value"},
+		{in: "trcItemDetails[=nl].title", err: "empty field name in [=nl]"},
+		{in: "calendar.singleDates[0]", err: "must end in a field name"},
+	}
+	for _, tt := range tests {
+		got, err := parseDocPath(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseDocPath(%q): error %v, want %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDocPath(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseDocPath(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if s := got.String(); s != strings.TrimSpace(tt.in) {
+			t.Errorf("parseDocPath(%q).String() = %q", tt.in, s)
+		}
+	}
+}
+
+// testDoc decodes a document written as JSON.
+func testDoc(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestDocPathGet(t *testing.T) {
+	doc := testDoc(t, `{
+		"title": "Concert",
+		"Location": {"address": {"city": "Utrecht"}},
+		"trcItemDetails": [{"lang": "en", "title": "Concert"}, {"lang": "nl", "title": "Concert NL"}],
+		"calendar": {"singleDates": [{"date": "2026-06-01"}, {"date": "2026-06-08", "seq": 2}]},
+		"types": ["a"]
+	}`)
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"title", "Concert", true},
+		{"location.ADDRESS.city", "Utrecht", true},
+		{"trcItemDetails[lang=nl].title", "Concert NL", true},
+		{"calendar.singleDates[1].date", "2026-06-08", true},
+		// Numbers match by their printed value.
+		{"calendar.singleDates[seq=2].date", "2026-06-08", true},
+		{"calendar.singleDates", doc["calendar"].(map[string]interface{})["singleDates"], true},
+		// Misses.
+		{"trcItemDetails[lang=de].title", nil, false},
+		{"calendar.singleDates[2].date", nil, false},
+		{"location.address.street", nil, false},
+		{"title.lang", nil, false},
+		{"types[0].id", nil, false},
+		{"title[0].x", nil, false},
+	}
+	for _, tt := range tests {
+		p, err := parseDocPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := p.get(doc)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("get %s = %v, %v; want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDocPathSet(t *testing.T) {
+	tests := []struct {
+		doc   string
+		path  string
+		value interface{}
+		want  string
+		err   string
+	}{
+		{doc: `{}`, path: "location.address.city", value: "Utrecht",
+			want: `{"location":{"address":{"city":"Utrecht"}}}`},
+		// Existing keys keep their case.
+		{doc: `{"Location":{"Address":{"City":"Amsterdam"}}}`, path: "location.address.city", value: "Utrecht",
+			want: `{"Location":{"Address":{"City":"Utrecht"}}}`},
+		// An index past the end grows the array with empty objects.
+		{doc: `{"calendar":{"singleDates":[{"date":"2026-06-01"}]}}`, path: "calendar.singleDates[2].date", value: "2026-06-15",
+			want: `{"calendar":{"singleDates":[{"date":"2026-06-01"},{},{"date":"2026-06-15"}]}}`},
+		{doc: `{"media":[null]}`, path: "media[0].url", value: "https://example.nl/a.jpg",
+			want: `{"media":[{"url":"https://example.nl/a.jpg"}]}`},
+		{doc: `{"trcItemDetails":[{"lang":"nl","title":"Oud"}]}`, path: "trcItemDetails[lang=nl].title", value: "Nieuw",
+			want: `{"trcItemDetails":[{"lang":"nl","title":"Nieuw"}]}`},
+		// A selector that matches nothing adds an element with its field.
+		{doc: `{"trcItemDetails":[{"lang":"nl","title":"Concert"}]}`, path: "trcItemDetails[lang=en].title", value: "Concert",
+			want: `{"trcItemDetails":[{"lang":"nl","title":"Concert"},{"lang":"en","title":"Concert"}]}`},
+		{doc: `{"title":"Concert"}`, path: "title.lang", value: "nl", err: "title is not an object"},
+		{doc: `{"types":"a"}`, path: "types[0].id", value: "x", err: "types is not an array"},
+		{doc: `{"types":["a"]}`, path: "types[0].id", value: "x", err: "element of types is not an object"},
+	}
+	for _, tt := range tests {
+		doc := testDoc(t, tt.doc)
+		p, err := parseDocPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.set(doc, tt.value)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("set %s on %s: error %v, want %q", tt.path, tt.doc, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("set %s on %s: %v", tt.path, tt.doc, err)
+			continue
+		}
+		if !reflect.DeepEqual(doc, testDoc(t, tt.want)) {
+			got, _ := json.Marshal(doc)
+			t.Errorf("set %s on %s:\n got %s\nwant %s", tt.path, tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestFindExisting(t *testing.T) {
+	// The server matches the filter on part of the value, like the API.
+	docs := []map[string]string{
+		{"id": "E1", "externalid": "ext-1", "trcid": "trc-1"},
+		{"id": "E2", "externalid": "ext-10", "trcid": "trc-10"},
+		{"id": "E3", "externalid": "ext-2", "trcid": "trc-2"},
+		{"id": "E4", "externalid": "ext-2", "trcid": "trc-20"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		field, key := "externalid", q.Get("externalid")
+		if key == "" {
+			field, key = "trcid", q.Get("trcid")
+		}
+		results := []map[string]string{}
+		for _, d := range docs {
+			if strings.Contains(d[field], key) {
+				results = append(results, d)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"size": 10, "page": 0, "hits": len(results), "results": results})
+	}))
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	tests := []struct {
+		field, key string
+		want       string
+		err        string
+	}{
+		{field: "externalid", key: "ext-1", want: "E1"},
+		{field: "trcid", key: "trc-1", want: "E1"},
+		{field: "trcid", key: "trc-2", want: "E3"},
+		{field: "externalid", key: "ext-3"},
+		{field: "trcid", key: "trc"},
+		{field: "externalid", key: "ext-2", err: "externalid ext-2 matches more than one resource"},
+	}
+	for _, tt := range tests {
+		got, err := findExisting(context.Background(), client, "events", tt.field, tt.key)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s %s: error %v, want %q", tt.field, tt.key, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s %s = %q, %v; want %q", tt.field, tt.key, got, err, tt.want)
+		}
+	}
+}
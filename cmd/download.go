@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeFileAtomic streams r into path; see writeAtomic. size is the expected
+// length for the progress display, or -1 when unknown.
+func writeFileAtomic(path string, r io.Reader, size int64) (int64, error) {
+	var n int64
+	err := writeAtomic(path, func(w io.Writer) error {
+		progress := &progressWriter{label: filepath.Base(path), total: size}
+		var err error
+		n, err = io.Copy(io.MultiWriter(w, progress), r)
+		progress.finish()
+		if err != nil {
+			return fmt.Errorf("downloading %s: %w", filepath.Base(path), err)
+		}
+		if size >= 0 && n != size {
+			return fmt.Errorf("downloading %s: got %s of %s", filepath.Base(path), formatBytes(n), formatBytes(size))
+		}
+		return nil
+	})
+	return n, err
+}
+
+// writeAtomic creates path with the data written by write. The data goes to a
+// temporary file next to path, which replaces path only once write has
+// succeeded, so a failed or interrupted command never leaves a truncated file
+// behind. A file that is replaced keeps its permissions; a new one gets 0644.
+//
+// The temporary file is removed on failure, so an interrupted download starts
+// over; exports are generated per request and can't be resumed.
+func writeAtomic(path string, write func(w io.Writer) error) error {
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		// A no-op after a successful rename.
+		os.Remove(tmpName)
+	}()
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
+
+// progressWriter counts the bytes written through it and shows the count on
+// stderr, at most a few times per second.
+type progressWriter struct {
+	label   string
+	total   int64
+	written int64
+	shown   time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if time.Since(p.shown) >= 200*time.Millisecond {
+		p.show()
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) show() {
+	p.shown = time.Now()
+	if p.total > 0 {
+		fmt.Fprintf(os.Stderr, "Downloading %s: %s of %s (%d%%)\r", p.label, formatBytes(p.written), formatBytes(p.total), p.written*100/p.total)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Downloading %s: %s\r", p.label, formatBytes(p.written))
+}
+
+// finish shows the final count and ends the progress line.
+func (p *progressWriter) finish() {
+	p.show()
+	fmt.Fprintln(os.Stderr)
+}
+
+// formatBytes returns n as a human-readable size, e.g. 1.5 MB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
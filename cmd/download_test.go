@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not kept on windows")
+	}
+	dir := t.TempDir()
+	write := func(s string) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		}
+	}
+
+	created := filepath.Join(dir, "new.xlsx")
+	if err := writeAtomic(created, write("new")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(created); fi.Mode().Perm() != 0644 {
+		t.Errorf("new file has mode %v, want 0644", fi.Mode().Perm())
+	}
+
+	private := filepath.Join(dir, "private.xlsx")
+	if err := os.WriteFile(private, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeAtomic(private, write("replaced")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(private); fi.Mode().Perm() != 0600 {
+		t.Errorf("replaced file has mode %v, want 0600", fi.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(private); string(data) != "replaced" {
+		t.Errorf("replaced file holds %q", data)
+	}
+
+	failed := errors.New("connection reset")
+	if err := writeAtomic(private, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return failed
+	}); !errors.Is(err, failed) {
+		t.Fatalf("error %v, want %v", err, failed)
+	}
+	if data, _ := os.ReadFile(private); string(data) != "replaced" {
+		t.Errorf("failed write changed the file to %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
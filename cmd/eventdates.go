@@ -0,0 +1,589 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/TheFeedFactory/tff-cli/internal/ical"
+)
+
+type EventsDatesCmd struct {
+	ID eventDatesTarget `arg:"" help:"ID of the event."`
+}
+
+// eventDatesTarget is the event whose dates the subcommands of 'events dates'
+// work on. Kong passes it to their Run methods.
+type eventDatesTarget struct {
+	ID      string               `arg:"" help:"ID of the event."`
+	List    EventDatesListCmd    `cmd:"" help:"List the single dates of the event with their times and whether they are cancelled or sold out."`
+	Add     EventDatesAddCmd     `cmd:"" help:"Add dates to the event: single dates, or a series up to --until (e.g. every Friday), or the dates of an iCalendar recurrence rule."`
+	Remove  EventDatesRemoveCmd  `cmd:"" help:"Remove dates from the event."`
+	Move    EventDatesMoveCmd    `cmd:"" help:"Move a date of the event to another day, and optionally other times."`
+	Cancel  EventDatesCancelCmd  `cmd:"" help:"Mark dates of the event as cancelled, or the whole event when no dates are given. --undo reverses it. Fails if the API did not keep the flag on a date."`
+	SoldOut EventDatesSoldOutCmd `cmd:"" name:"soldout" help:"Mark dates of the event as sold out, or the whole event when no dates are given. --undo reverses it. Fails if the API did not keep the flag on a date."`
+}
+
+// dateEditFlags are the flags of the 'events dates' commands that change the
+// calendar.
+type dateEditFlags struct {
+	DryRun bool `name:"dry-run" help:"Show the new calendar without saving it."`
+	Force  bool `short:"f" help:"Save without a confirmation prompt."`
+}
+
+// dateRow is a single date in the output of 'events dates', with the change an
+// edit makes to it in the preview.
+type dateRow struct {
+	date   feedfactory.SingleDate
+	change string
+}
+
+var dateColumns = []column[dateRow]{
+	{Name: "date", Value: func(r dateRow) string { return r.date.Date }},
+	{Name: "day", Value: func(r dateRow) string {
+		t, err := time.Parse(dateLayout, r.date.Date)
+		if err != nil {
+			return ""
+		}
+		return t.Format("Mon")
+	}},
+	{Name: "start", Value: func(r dateRow) string { return r.date.StartTime }},
+	{Name: "end", Value: func(r dateRow) string { return r.date.EndTime }},
+	{Name: "status", Value: func(r dateRow) string { return dateStatus(r.date) }},
+}
+
+var dateChangeColumn = column[dateRow]{Name: "change", Value: func(r dateRow) string { return r.change }}
+
+func dateStatus(d feedfactory.SingleDate) string {
+	var status []string
+	if d.Cancelled {
+		status = append(status, "cancelled")
+	}
+	if d.SoldOut {
+		status = append(status, "sold out")
+	}
+	return strings.Join(status, ", ")
+}
+
+type EventDatesListCmd struct {
+	JSON bool `short:"j" help:"Output JSON instead of a table."`
+}
+
+func (c *EventDatesListCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	e, err := client.GetEvent(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	cal := e.Calendar
+	if cal == nil {
+		cal = &feedfactory.Calendar{}
+	}
+	rows := make([]dateRow, len(cal.SingleDates))
+	for i, d := range cal.SingleDates {
+		rows[i] = dateRow{date: d}
+	}
+	return view[dateRow]{
+		Columns:  dateColumns,
+		Rows:     rows,
+		Doc:      func(r dateRow) interface{} { return r.date },
+		Document: cal,
+		Empty:    "The event has no single dates.",
+		Footer:   calendarFooter(cal, nil),
+	}.render(c.JSON)
+}
+
+type EventDatesAddCmd struct {
+	Dates []string `arg:"" help:"Dates to add, as yyyy-mm-dd or an expression such as tomorrow or +2w. With --until or --rrule, the first date of the series."`
+	Start string   `help:"Start time (hh:mm)."`
+	End   string   `help:"End time (hh:mm)."`
+	Until string   `help:"Repeat from each date up to and including this date, see --every."`
+	Every string   `help:"How to repeat up to --until: day, week (default), month, or weekdays such as fr or sa,su."`
+	RRule string   `name:"rrule" help:"Repeat with an iCalendar recurrence rule, e.g. FREQ=WEEKLY;BYDAY=FR;COUNT=6 or FREQ=MONTHLY;BYDAY=1SU. Without COUNT or UNTIL the series ends at --until, or a year after the first date."`
+	Max   int      `default:"500" help:"Maximum number of dates a series adds. Default: 500."`
+	dateEditFlags
+}
+
+func (c *EventDatesAddCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	start, end, err := dateTimes(c.Start, c.End)
+	if err != nil {
+		return err
+	}
+	rule, weekdays, err := c.rule()
+	if err != nil {
+		return err
+	}
+
+	var added []feedfactory.SingleDate
+	for _, expr := range c.Dates {
+		day, err := ParseDateFrom(expr)
+		if err != nil {
+			return fmt.Errorf("invalid date %q: %w", expr, err)
+		}
+		if rule == "" {
+			added = append(added, feedfactory.SingleDate{Date: day, StartTime: start, EndTime: end})
+			continue
+		}
+
+		first, _ := time.ParseInLocation(dateLayout+" 15:04", day+" "+orMidnight(start), apiLocation)
+		limit := first.AddDate(1, 0, 0)
+		if c.Until != "" {
+			untilDay, err := ParseDateTo(c.Until)
+			if err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			u, _ := time.ParseInLocation(dateLayout, untilDay, apiLocation)
+			limit = u.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		starts, err := ical.Expand(rule, first, limit, c.Max)
+		if err != nil {
+			return err
+		}
+		for _, s := range starts {
+			// A series on given weekdays, by --every or a BYDAY in --rrule,
+			// leaves out a first date on another day.
+			if len(weekdays) > 0 && !weekdays[s.Weekday()] {
+				continue
+			}
+			added = append(added, feedfactory.SingleDate{Date: s.Format(dateLayout), StartTime: start, EndTime: end})
+		}
+	}
+
+	return c.edit(ctx, client, t.ID, func(cal *feedfactory.Calendar) error {
+		present := map[string]bool{}
+		for _, d := range cal.SingleDates {
+			present[dateKey(d)] = true
+		}
+		for _, d := range added {
+			if !present[dateKey(d)] {
+				cal.SingleDates = append(cal.SingleDates, d)
+				present[dateKey(d)] = true
+			}
+		}
+		return nil
+	}, nil)
+}
+
+// dateWeekdays are the names --every accepts for weekdays, by their first two
+// letters.
+var dateWeekdays = map[string]time.Weekday{
+	"mo": time.Monday, "tu": time.Tuesday, "we": time.Wednesday, "th": time.Thursday,
+	"fr": time.Friday, "sa": time.Saturday, "su": time.Sunday,
+}
+
+// rule returns the recurrence rule of the flags, or "" for single dates, and
+// the weekdays of a series on given weekdays.
+func (c *EventDatesAddCmd) rule() (string, map[time.Weekday]bool, error) {
+	if c.RRule != "" {
+		if c.Every != "" {
+			return "", nil, fmt.Errorf("--every cannot be combined with --rrule")
+		}
+		rule := strings.TrimPrefix(c.RRule, "RRULE:")
+		return rule, ruleWeekdays(rule), nil
+	}
+	if c.Until == "" {
+		if c.Every != "" {
+			return "", nil, fmt.Errorf("--every needs --until")
+		}
+		return "", nil, nil
+	}
+
+	every := strings.ToLower(c.Every)
+	switch every {
+	case "", "week", "weekly":
+		return "FREQ=WEEKLY", nil, nil
+	case "day", "daily":
+		return "FREQ=DAILY", nil, nil
+	case "month", "monthly":
+		return "FREQ=MONTHLY", nil, nil
+	}
+	weekdays := map[time.Weekday]bool{}
+	var byDay []string
+	for _, name := range strings.Split(every, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) < 2 {
+			return "", nil, fmt.Errorf("invalid --every %q (use day, week, month or weekdays such as fr or sa,su)", c.Every)
+		}
+		day, ok := dateWeekdays[name[:2]]
+		if !ok {
+			return "", nil, fmt.Errorf("invalid --every %q (use day, week, month or weekdays such as fr or sa,su)", c.Every)
+		}
+		weekdays[day] = true
+		byDay = append(byDay, strings.ToUpper(name[:2]))
+	}
+	return "FREQ=WEEKLY;BYDAY=" + strings.Join(byDay, ","), weekdays, nil
+}
+
+// ruleWeekdays returns the weekdays in the BYDAY part of a recurrence rule, or
+// nil when it has none. Ordinals are ignored: the first date of
+// FREQ=MONTHLY;BYDAY=1SU only has to be a Sunday.
+func ruleWeekdays(rule string) map[time.Weekday]bool {
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		if !strings.EqualFold(strings.TrimSpace(k), "BYDAY") {
+			continue
+		}
+		weekdays := map[time.Weekday]bool{}
+		for _, d := range strings.Split(strings.ToLower(v), ",") {
+			d = strings.TrimSpace(d)
+			if len(d) >= 2 {
+				if day, ok := dateWeekdays[d[len(d)-2:]]; ok {
+					weekdays[day] = true
+				}
+			}
+		}
+		return weekdays
+	}
+	return nil
+}
+
+type EventDatesRemoveCmd struct {
+	Dates []string `arg:"" optional:"" help:"Dates to remove, as yyyy-mm-dd or an expression such as tomorrow."`
+	Time  string   `help:"Only remove the dates that start at this time (hh:mm)."`
+	Past  bool     `help:"Remove all dates before today."`
+	dateEditFlags
+}
+
+func (c *EventDatesRemoveCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	if len(c.Dates) == 0 && !c.Past {
+		return fmt.Errorf("give the dates to remove, or --past")
+	}
+	today := time.Now().In(apiLocation).Format(dateLayout)
+	return c.edit(ctx, client, t.ID, func(cal *feedfactory.Calendar) error {
+		remove, err := selectDates(cal, c.Dates, c.Time)
+		if err != nil {
+			return err
+		}
+		var kept []feedfactory.SingleDate
+		for i, d := range cal.SingleDates {
+			if remove[i] || (c.Past && d.Date < today) {
+				continue
+			}
+			kept = append(kept, d)
+		}
+		cal.SingleDates = kept
+		return nil
+	}, nil)
+}
+
+type EventDatesMoveCmd struct {
+	Date  string `arg:"" help:"Date to move."`
+	To    string `arg:"" help:"Date to move it to."`
+	Time  string `help:"The date to move when the event has several on the day: the one starting at this time (hh:mm)."`
+	Start string `help:"New start time (hh:mm). Default: unchanged."`
+	End   string `help:"New end time (hh:mm). Default: unchanged."`
+	dateEditFlags
+}
+
+func (c *EventDatesMoveCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	to, err := ParseDateFrom(c.To)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", c.To, err)
+	}
+	start, end, err := dateTimes(c.Start, c.End)
+	if err != nil {
+		return err
+	}
+	return c.edit(ctx, client, t.ID, func(cal *feedfactory.Calendar) error {
+		selected, err := selectDates(cal, []string{c.Date}, c.Time)
+		if err != nil {
+			return err
+		}
+		if len(selected) > 1 {
+			return fmt.Errorf("the event has %d dates on %s; choose one with --time", len(selected), c.Date)
+		}
+		for i := range selected {
+			d := &cal.SingleDates[i]
+			d.Date = to
+			if start != "" {
+				d.StartTime = start
+			}
+			if end != "" {
+				d.EndTime = end
+			}
+			for j, other := range cal.SingleDates {
+				if j != i && dateKey(other) == dateKey(*d) {
+					return fmt.Errorf("the event already has a date on %s", strings.TrimSpace(dateKey(*d)))
+				}
+			}
+		}
+		return nil
+	}, nil)
+}
+
+// dateMarkFlags are the arguments of 'events dates cancel' and 'soldout'.
+type dateMarkFlags struct {
+	Dates []string `arg:"" optional:"" help:"Dates to mark, as yyyy-mm-dd or an expression such as tomorrow. Without dates the whole event is marked."`
+	Time  string   `help:"Only mark the dates that start at this time (hh:mm)."`
+	Undo  bool     `help:"Remove the mark instead of setting it."`
+	dateEditFlags
+}
+
+// mark sets or clears a flag on the selected dates, or on the calendar when no
+// dates are given. The API documentation doesn't describe the flags of single
+// dates, so after saving them the event is read back, and mark fails if a date
+// didn't keep its flag.
+func (f *dateMarkFlags) mark(ctx context.Context, client *feedfactory.Client, id, name string, flag func(d *feedfactory.SingleDate) *bool, calFlag func(cal *feedfactory.Calendar) *bool) error {
+	if len(f.Dates) == 0 && f.Time != "" {
+		return fmt.Errorf("--time needs dates")
+	}
+	// marked holds the dates that were changed, by dateKey.
+	marked := map[string]bool{}
+	edit := func(cal *feedfactory.Calendar) error {
+		if len(f.Dates) == 0 {
+			*calFlag(cal) = !f.Undo
+			return nil
+		}
+		selected, err := selectDates(cal, f.Dates, f.Time)
+		if err != nil {
+			return err
+		}
+		for i := range selected {
+			d := &cal.SingleDates[i]
+			if *flag(d) != !f.Undo {
+				marked[dateKey(*d)] = true
+			}
+			*flag(d) = !f.Undo
+		}
+		return nil
+	}
+	if len(f.Dates) == 0 {
+		return f.edit(ctx, client, id, edit, nil)
+	}
+	check := func(saved *feedfactory.Calendar) error {
+		var lost []string
+		for _, d := range saved.SingleDates {
+			if marked[dateKey(d)] && *flag(&d) == f.Undo {
+				lost = append(lost, strings.TrimSpace(dateKey(d)))
+			}
+		}
+		if len(lost) == 0 {
+			return nil
+		}
+		verb := "is"
+		if len(lost) > 1 {
+			verb = "are"
+		}
+		if f.Undo {
+			return fmt.Errorf("the API did not keep the change: %s %s still marked %s", strings.Join(lost, ", "), verb, name)
+		}
+		return fmt.Errorf("the API did not keep the change: %s %s not marked %s", strings.Join(lost, ", "), verb, name)
+	}
+	return f.edit(ctx, client, id, edit, check)
+}
+
+type EventDatesCancelCmd struct {
+	dateMarkFlags
+}
+
+func (c *EventDatesCancelCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	return c.mark(ctx, client, t.ID, "cancelled",
+		func(d *feedfactory.SingleDate) *bool { return &d.Cancelled },
+		func(cal *feedfactory.Calendar) *bool { return &cal.Cancelled })
+}
+
+type EventDatesSoldOutCmd struct {
+	dateMarkFlags
+}
+
+func (c *EventDatesSoldOutCmd) Run(ctx context.Context, client *feedfactory.Client, t *eventDatesTarget) error {
+	return c.mark(ctx, client, t.ID, "sold out",
+		func(d *feedfactory.SingleDate) *bool { return &d.SoldOut },
+		func(cal *feedfactory.Calendar) *bool { return &cal.SoldOut })
+}
+
+// edit fetches the event, lets fn change its calendar, shows the new calendar
+// with the changes, and saves it after confirmation. If check is not nil, the
+// event is read back after saving and check is called with its calendar.
+func (f *dateEditFlags) edit(ctx context.Context, client *feedfactory.Client, id string, fn func(cal *feedfactory.Calendar) error, check func(saved *feedfactory.Calendar) error) error {
+	e, err := client.GetEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Calendar == nil {
+		e.Calendar = &feedfactory.Calendar{}
+	}
+	before := *e.Calendar
+	before.SingleDates = append([]feedfactory.SingleDate(nil), e.Calendar.SingleDates...)
+
+	if err := fn(e.Calendar); err != nil {
+		return err
+	}
+	sortDates(e.Calendar.SingleDates)
+
+	rows, changed := dateChanges(&before, e.Calendar)
+	if !changed {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	fmt.Printf("New calendar of event %s:\n\n", id)
+	err = view[dateRow]{
+		Columns: append(append([]column[dateRow](nil), dateColumns...), dateChangeColumn),
+		Rows:    rows,
+		Doc: func(r dateRow) interface{} {
+			return map[string]interface{}{"date": r.date, "change": r.change}
+		},
+		Empty:  "The event has no single dates.",
+		Footer: calendarFooter(e.Calendar, &before),
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if f.DryRun {
+		fmt.Println("\nDry run: the calendar was not saved.")
+		return nil
+	}
+	if !f.Force && !confirm(fmt.Sprintf("\nSave the new calendar of event %s?", id)) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	if err := client.UpdateEvent(ctx, e); err != nil {
+		return fmt.Errorf("saving calendar: %w", err)
+	}
+	n := len(e.Calendar.SingleDates)
+	fmt.Printf("Saved the calendar of event %s (%d %s).\n", id, n, pluralize("date", n))
+	if check == nil {
+		return nil
+	}
+	saved, err := client.GetEvent(ctx, id)
+	if err != nil {
+		return fmt.Errorf("reading back event %s: %w", id, err)
+	}
+	if saved.Calendar == nil {
+		saved.Calendar = &feedfactory.Calendar{}
+	}
+	return check(saved.Calendar)
+}
+
+// dateChanges returns the dates of the new calendar and the removed ones, in
+// order, with what changed about each, and reports whether anything changed.
+func dateChanges(before, after *feedfactory.Calendar) ([]dateRow, bool) {
+	old := map[string]feedfactory.SingleDate{}
+	for _, d := range before.SingleDates {
+		old[dateKey(d)] = d
+	}
+	changed := before.Cancelled != after.Cancelled || before.SoldOut != after.SoldOut
+
+	var rows []dateRow
+	kept := map[string]bool{}
+	for _, d := range after.SingleDates {
+		row := dateRow{date: d}
+		prev, ok := old[dateKey(d)]
+		kept[dateKey(d)] = true
+		switch {
+		case !ok:
+			row.change = "added"
+		case prev.Cancelled != d.Cancelled && d.Cancelled:
+			row.change = "cancelled"
+		case prev.Cancelled != d.Cancelled:
+			row.change = "no longer cancelled"
+		case prev.SoldOut != d.SoldOut && d.SoldOut:
+			row.change = "sold out"
+		case prev.SoldOut != d.SoldOut:
+			row.change = "no longer sold out"
+		case prev.EndTime != d.EndTime:
+			row.change = "end time changed"
+		}
+		changed = changed || row.change != ""
+		rows = append(rows, row)
+	}
+	for _, d := range before.SingleDates {
+		if !kept[dateKey(d)] {
+			rows = append(rows, dateRow{date: d, change: "removed"})
+			changed = true
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return dateKey(rows[i].date) < dateKey(rows[j].date) })
+	return rows, changed
+}
+
+// calendarFooter describes the flags of the whole calendar, and how they
+// change from before, if given.
+func calendarFooter(cal, before *feedfactory.Calendar) string {
+	var lines []string
+	flag := func(now, was bool, name string) {
+		switch {
+		case before != nil && now && !was:
+			lines = append(lines, "The event will be marked "+name+".")
+		case before != nil && !now && was:
+			lines = append(lines, "The event will no longer be marked "+name+".")
+		case now:
+			lines = append(lines, "The event is marked "+name+".")
+		}
+	}
+	var wasCancelled, wasSoldOut bool
+	if before != nil {
+		wasCancelled, wasSoldOut = before.Cancelled, before.SoldOut
+	}
+	flag(cal.Cancelled, wasCancelled, "cancelled")
+	flag(cal.SoldOut, wasSoldOut, "sold out")
+	return strings.Join(lines, "\n")
+}
+
+// selectDates returns the indexes of the single dates on the given days,
+// limited to those starting at tm if given. Every day must have a date.
+func selectDates(cal *feedfactory.Calendar, days []string, tm string) (map[int]bool, error) {
+	if tm != "" {
+		t, err := parseImportTime(tm)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --time: %w", err)
+		}
+		tm = t
+	}
+	selected := map[int]bool{}
+	for _, expr := range days {
+		day, err := ParseDateFrom(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", expr, err)
+		}
+		found := false
+		for i, d := range cal.SingleDates {
+			if d.Date == day && (tm == "" || d.StartTime == tm) {
+				selected[i] = true
+				found = true
+			}
+		}
+		if !found && tm != "" {
+			return nil, fmt.Errorf("the event has no date on %s at %s", day, tm)
+		}
+		if !found {
+			return nil, fmt.Errorf("the event has no date on %s", day)
+		}
+	}
+	return selected, nil
+}
+
+// dateTimes checks and normalizes the --start and --end flags.
+func dateTimes(start, end string) (string, string, error) {
+	var err error
+	if start != "" {
+		if start, err = parseImportTime(start); err != nil {
+			return "", "", fmt.Errorf("invalid --start: %w", err)
+		}
+	}
+	if end != "" {
+		if end, err = parseImportTime(end); err != nil {
+			return "", "", fmt.Errorf("invalid --end: %w", err)
+		}
+	}
+	return start, end, nil
+}
+
+func orMidnight(tm string) string {
+	if tm == "" {
+		return "00:00"
+	}
+	return tm
+}
+
+// dateKey identifies a single date by its day and start time, and sorts them.
+func dateKey(d feedfactory.SingleDate) string {
+	return d.Date + " " + d.StartTime
+}
+
+func sortDates(dates []feedfactory.SingleDate) {
+	sort.SliceStable(dates, func(i, j int) bool { return dateKey(dates[i]) < dateKey(dates[j]) })
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestRuleWeekdays(t *testing.T) {
+	tests := []struct {
+		rule string
+		want map[time.Weekday]bool
+	}{
+		{"FREQ=WEEKLY;COUNT=6", nil},
+		{"FREQ=WEEKLY;BYDAY=FR;COUNT=6", map[time.Weekday]bool{time.Friday: true}},
+		{"freq=weekly;byday=sa, su", map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}},
+		{"FREQ=MONTHLY;BYDAY=1SU,-1FR", map[time.Weekday]bool{time.Sunday: true, time.Friday: true}},
+	}
+	for _, tt := range tests {
+		if got := ruleWeekdays(tt.rule); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+// TestMarkDatesReadBack checks that marking dates fails when the server drops
+// the per-date flag on save.
+func TestMarkDatesReadBack(t *testing.T) {
+	for _, keep := range []bool{true, false} {
+		doc := []byte(`{"id":"E1","calendar":{"singleDates":[{"date":"2026-06-01","starttime":"20:00"},{"date":"2026-06-08","starttime":"20:00"}]}}`)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/events/E1" {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Method == http.MethodPut {
+				body, _ := io.ReadAll(r.Body)
+				if !keep {
+					body = []byte(strings.ReplaceAll(string(body), `"cancelled":true`, `"cancelled":false`))
+				}
+				doc = body
+			}
+			w.Write(doc)
+		}))
+		client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+		cmd := EventDatesCancelCmd{dateMarkFlags{Dates: []string{"2026-06-08"}, dateEditFlags: dateEditFlags{Force: true}}}
+		err := cmd.Run(context.Background(), client, &eventDatesTarget{ID: "E1"})
+		srv.Close()
+		if keep {
+			if err != nil {
+				t.Errorf("server keeps the flag: %v", err)
+			}
+			var e feedfactory.Event
+			if err := json.Unmarshal(doc, &e); err != nil {
+				t.Fatal(err)
+			}
+			if d := e.Calendar.SingleDates; d[0].Cancelled || !d[1].Cancelled {
+				t.Errorf("saved dates %+v", d)
+			}
+			continue
+		}
+		if want := "the API did not keep the change: 2026-06-08 20:00 is not marked cancelled"; err == nil || err.Error() != want {
+			t.Errorf("server drops the flag: error %v, want %q", err, want)
+		}
+	}
+}
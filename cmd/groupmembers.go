@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type EventGroupsMembersCmd struct {
+	ID groupMembersTarget `arg:"" help:"ID of the event group."`
+}
+
+// groupMembersTarget is the event group whose events the subcommands of
+// 'eventgroups members' work on. Kong passes it to their Run methods.
+type groupMembersTarget struct {
+	ID     string                `arg:"" help:"ID of the event group."`
+	List   GroupMembersListCmd   `cmd:"" default:"withargs" help:"List the events of the group with their dates and status. This is the default."`
+	Add    GroupMembersAddCmd    `cmd:"" help:"Add events to the group."`
+	Remove GroupMembersRemoveCmd `cmd:"" help:"Remove events from the group. The events themselves are kept."`
+}
+
+// memberRow is an event of a group. The event is nil when it couldn't be
+// fetched; status then says why.
+type memberRow struct {
+	id     string
+	event  *feedfactory.Event
+	raw    json.RawMessage
+	status string
+}
+
+var memberColumns = []column[memberRow]{
+	{Name: "id", Value: func(r memberRow) string { return r.id }},
+	{Name: "title", Width: 40, Value: func(r memberRow) string {
+		if r.event == nil {
+			return ""
+		}
+		return r.event.GetTitle()
+	}},
+	{Name: "dates", Value: func(r memberRow) string {
+		if r.event == nil {
+			return ""
+		}
+		return dateSpan(r.event.Calendar)
+	}},
+	{Name: "status", Value: func(r memberRow) string {
+		if r.event == nil {
+			return r.status
+		}
+		status := r.event.WFStatus
+		if r.event.Calendar != nil && r.event.Calendar.Cancelled {
+			status += ", cancelled"
+		}
+		return status
+	}},
+	{Name: "published", Value: func(r memberRow) string {
+		if r.event == nil {
+			return ""
+		}
+		return boolYesNo(r.event.Published)
+	}},
+}
+
+// dateSpan summarizes the single dates of a calendar: the first and last date
+// and how many there are.
+func dateSpan(cal *feedfactory.Calendar) string {
+	if cal == nil || len(cal.SingleDates) == 0 {
+		return ""
+	}
+	first, last := cal.SingleDates[0].Date, cal.SingleDates[0].Date
+	for _, d := range cal.SingleDates {
+		first, last = min(first, d.Date), max(last, d.Date)
+	}
+	n := len(cal.SingleDates)
+	if n == 1 {
+		return first
+	}
+	return fmt.Sprintf("%s .. %s (%d %s)", first, last, n, pluralize("date", n))
+}
+
+type GroupMembersListCmd struct {
+	JSON bool `short:"j" help:"Output JSON instead of a table."`
+}
+
+func (c *GroupMembersListCmd) Run(ctx context.Context, client *feedfactory.Client, t *groupMembersTarget) error {
+	g, err := client.GetEventGroup(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	rows, err := fetchMembers(ctx, client, g.Events.IDs)
+	if err != nil {
+		return err
+	}
+	return view[memberRow]{
+		Columns: memberColumns,
+		Rows:    rows,
+		Doc: func(r memberRow) interface{} {
+			if r.event == nil {
+				return map[string]string{"id": r.id, "error": r.status}
+			}
+			return rawDoc(r.raw)
+		},
+		Empty:  fmt.Sprintf("Event group %s has no events.", t.ID),
+		Footer: fmt.Sprintf("%d %s", len(rows), pluralize("event", len(rows))),
+	}.render(c.JSON)
+}
+
+// fetchMembers fetches the events with the given IDs. Events that don't exist
+// get a row with status "not found".
+func fetchMembers(ctx context.Context, client *feedfactory.Client, ids []string) ([]memberRow, error) {
+	rows := make([]memberRow, 0, len(ids))
+	for _, id := range ids {
+		raw, err := client.GetResource(ctx, "events", id)
+		if err != nil && ctx.Err() != nil {
+			return nil, stopped(ctx, "after fetching %d of %d events", len(rows), len(ids))
+		}
+		var apiErr *feedfactory.APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			rows = append(rows, memberRow{id: id, status: "not found"})
+		case err != nil:
+			return nil, fmt.Errorf("fetching event %s: %w", id, err)
+		default:
+			var e feedfactory.Event
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return nil, fmt.Errorf("parsing event %s: %w", id, err)
+			}
+			rows = append(rows, memberRow{id: id, event: &e, raw: raw})
+		}
+	}
+	return rows, nil
+}
+
+// memberEditFlags are the flags of the 'eventgroups members' commands that
+// change the group.
+type memberEditFlags struct {
+	DryRun bool `name:"dry-run" help:"Show the change without saving it."`
+	Force  bool `short:"f" help:"Save without a confirmation prompt."`
+}
+
+type GroupMembersAddCmd struct {
+	Events []string `arg:"" help:"IDs of the events to add."`
+	memberEditFlags
+}
+
+func (c *GroupMembersAddCmd) Run(ctx context.Context, client *feedfactory.Client, t *groupMembersTarget) error {
+	g, err := client.GetEventGroup(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range c.Events {
+		if contains(g.Events.IDs, id) || contains(ids, id) {
+			fmt.Printf("Event %s is already in the group.\n", id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	// Only existing events can be added.
+	rows, err := fetchMembers(ctx, client, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if r.event == nil {
+			return fmt.Errorf("event %s: %s", r.id, r.status)
+		}
+	}
+
+	g.Events.IDs = append(g.Events.IDs, ids...)
+	return c.save(ctx, client, g, "Adding", "to", rows)
+}
+
+type GroupMembersRemoveCmd struct {
+	Events []string `arg:"" help:"IDs of the events to remove."`
+	memberEditFlags
+}
+
+func (c *GroupMembersRemoveCmd) Run(ctx context.Context, client *feedfactory.Client, t *groupMembersTarget) error {
+	g, err := client.GetEventGroup(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range c.Events {
+		if !contains(g.Events.IDs, id) {
+			return fmt.Errorf("event %s is not in event group %s", id, t.ID)
+		}
+	}
+	rows, err := fetchMembers(ctx, client, c.Events)
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, id := range g.Events.IDs {
+		if !contains(c.Events, id) {
+			kept = append(kept, id)
+		}
+	}
+	g.Events.IDs = kept
+	if g.Events.IDs == nil {
+		g.Events.IDs = []string{}
+	}
+	return c.save(ctx, client, g, "Removing", "from", rows)
+}
+
+// save shows the events that are added or removed and saves the group after
+// confirmation.
+func (f *memberEditFlags) save(ctx context.Context, client *feedfactory.Client, g *feedfactory.EventGroup, verb, prep string, rows []memberRow) error {
+	n := len(rows)
+	fmt.Printf("%s %d %s %s event group %s (%s):\n\n", verb, n, pluralize("event", n), prep, g.ID, g.GetTitle())
+	err := view[memberRow]{
+		Columns: memberColumns,
+		Rows:    rows,
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if f.DryRun {
+		fmt.Println("\nDry run: the event group was not saved.")
+		return nil
+	}
+	if !f.Force && !confirm(fmt.Sprintf("\nSave event group %s?", g.ID)) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	if err := client.UpdateEventGroup(ctx, g); err != nil {
+		return fmt.Errorf("saving event group: %w", err)
+	}
+	total := len(g.Events.IDs)
+	fmt.Printf("Saved event group %s (%d %s).\n", g.ID, total, pluralize("event", total))
+	return nil
+}
+
+// groupRef is an event group an event belongs to, in the output of
+// 'events get --groups' and 'events groups'.
+type groupRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// EventsGroupsCmd lists only the groups that 'events get --groups' adds to the
+// detail view.
+type EventsGroupsCmd struct {
+	ID   string `arg:"" help:"ID of the event."`
+	JSON bool   `short:"j" help:"Output JSON instead of a table."`
+}
+
+func (c *EventsGroupsCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	groups, err := eventGroupsOf(ctx, client, c.ID)
+	if err != nil {
+		return err
+	}
+	return view[groupRef]{
+		Columns: []column[groupRef]{
+			{Name: "id", Value: func(g groupRef) string { return g.ID }},
+			{Name: "title", Width: 60, Value: func(g groupRef) string { return g.Title }},
+		},
+		Rows:  groups,
+		Empty: fmt.Sprintf("Event %s is in no event group.", c.ID),
+	}.render(c.JSON)
+}
+
+// groupFields are the fields eventGroupsOf fetches of each group.
+var groupFields = []string{"id", "trcItemDetails.lang", "trcItemDetails.title", "events"}
+
+// eventGroupsOf returns the event groups that have the event with the given ID.
+// Groups don't link back from their events and the API has no filter on the
+// events of a group, so this reads all groups, with only the fields it needs.
+func eventGroupsOf(ctx context.Context, client *feedfactory.Client, id string) ([]groupRef, error) {
+	groups, err := fetchAllResources(ctx, client, "eventgroups", feedfactory.ListOptions{Fields: groupFields}, 0)
+	if err != nil {
+		return nil, err
+	}
+	refs := []groupRef{}
+	for _, g := range groups {
+		if contains(g.Events.IDs, id) {
+			refs = append(refs, groupRef{ID: g.ID, Title: g.GetTitle()})
+		}
+	}
+	return refs, nil
+}
+
+// withField adds a field to a JSON object, keeping the order of the others.
+func withField(doc []byte, key string, v interface{}) ([]byte, error) {
+	doc = bytes.TrimSpace(doc)
+	if len(doc) < 2 || doc[0] != '{' {
+		return nil, fmt.Errorf("adding %s: not a JSON object", key)
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := json.Marshal(key)
+	out := append([]byte(nil), doc[:len(doc)-1]...)
+	if len(bytes.TrimSpace(out)) > 1 {
+		out = append(out, ',')
+	}
+	out = append(append(append(out, name...), ':'), value...)
+	return append(out, '}'), nil
+}
+
+// printGroups prints the event groups of an event below its detail view.
+func printGroups(groups []groupRef) {
+	if len(groups) == 0 {
+		fmt.Println("\nEvent Groups: none")
+		return
+	}
+	fmt.Println("\nEvent Groups:")
+	for _, g := range groups {
+		fmt.Printf("  %s  %s\n", g.ID, strings.TrimSpace(g.Title))
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestEventGroupsOf(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eventgroups" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("fields"); got != "id,trcItemDetails.lang,trcItemDetails.title,events" {
+			t.Errorf("fields %q", got)
+		}
+		w.Write([]byte(`{"size":3,"page":0,"hits":3,"results":[
+			{"id":"G1","trcItemDetails":[{"lang":"en","title":"Summer"},{"lang":"nl","title":"Zomer"}],"events":["E1","E2"]},
+			{"id":"G2","trcItemDetails":[{"lang":"nl","title":"Winter"}],"events":[{"id":"E3"}]},
+			{"id":"G3","trcItemDetails":[{"lang":"nl","title":"Festival"}],"events":[{"id":"E2","order":1},{"ref":"x"}]}
+		]}`))
+	}))
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	groups, err := eventGroupsOf(context.Background(), client, "E2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []groupRef{{ID: "G1", Title: "Zomer"}, {ID: "G3", Title: "Festival"}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("got %+v, want %+v", groups, want)
+	}
+}
+
+func TestWithField(t *testing.T) {
+	groups := []groupRef{{ID: "G1", Title: "Zomer"}}
+	tests := []struct{ doc, want string }{
+		{`{"id":"E1","title":"Concert"}`, `{"id":"E1","title":"Concert","eventgroups":[{"id":"G1","title":"Zomer"}]}`},
+		{" {} \n", `{"eventgroups":[{"id":"G1","title":"Zomer"}]}`},
+	}
+	for _, tt := range tests {
+		got, err := withField([]byte(tt.doc), "eventgroups", groups)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("withField(%q) = %s, %v; want %s", tt.doc, got, err, tt.want)
+		}
+	}
+	if _, err := withField([]byte(`[]`), "eventgroups", groups); err == nil {
+		t.Error("expected an error for an array")
+	}
+}
+
+func TestGetGroupsOnlyForEvents(t *testing.T) {
+	cmd := ResourceGetCmd[locationKind]{ID: "L1", Groups: true}
+	if err := cmd.Run(context.Background(), feedfactory.New()); err == nil || err.Error() != "--groups is only available for events" {
+		t.Errorf("error %v", err)
+	}
+}
@@ -0,0 +1,439 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/TheFeedFactory/tff-cli/internal/ical"
+	"gopkg.in/yaml.v3"
+)
+
+type EventsImportICalCmd struct {
+	Source     string        `arg:"" help:"iCalendar (.ics) file, or the URL of a feed (http, https or webcal)."`
+	LocationID string        `name:"location-id" help:"ID of the location where the events take place. Its name and address are copied into every event instead of the LOCATION of the feed."`
+	Defaults   string        `type:"existingfile" help:"YAML file with fields to set on created events, by document path, e.g. wfstatus: draft or translations.primaryLanguage: nl."`
+	Marker     string        `help:"Marker that identifies the events of this feed. Imported events get it, and upcoming events with it that are no longer in the feed are marked cancelled. Without --marker removed events are left alone."`
+	Lang       string        `default:"nl" help:"Language of the titles and descriptions in the feed. Default: nl."`
+	From       string        `default:"today" help:"Import dates from this date on. Dates an existing event has before it are kept. Accepts the same expressions as --date-from. Default: today."`
+	Until      string        `default:"+1y" help:"Expand recurring events up to this date. Accepts the same expressions as --date-to. Default: +1y."`
+	MaxDates   int           `name:"max-dates" default:"500" help:"Maximum number of dates per event. Default: 500."`
+	DryRun     bool          `name:"dry-run" help:"Show what would be created, updated and cancelled without saving anything."`
+	Timeout    time.Duration `name:"request-timeout" default:"30s" help:"Timeout for downloading a feed URL. Default: 30s."`
+}
+
+// icalResult is the outcome of importing one event of the feed.
+type icalResult struct {
+	UID     string `json:"uid"`
+	Title   string `json:"title"`
+	Action  string `json:"action"`
+	ID      string `json:"id,omitempty"`
+	Dates   int    `json:"dates"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+var icalColumns = []column[icalResult]{
+	{Name: "uid", Width: 30, Value: func(r icalResult) string { return r.UID }},
+	{Name: "title", Width: 40, Value: func(r icalResult) string { return r.Title }},
+	{Name: "action", Value: func(r icalResult) string { return r.Action }},
+	{Name: "id", Value: func(r icalResult) string { return r.ID }},
+	{Name: "dates", Value: func(r icalResult) string { return strconv.Itoa(r.Dates) }},
+	{Name: "status", Value: func(r icalResult) string { return r.Status }},
+	{Name: "message", Width: 60, Value: func(r icalResult) string { return r.Message }},
+}
+
+func (c *EventsImportICalCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	if c.MaxDates < 1 {
+		return fmt.Errorf("--max-dates must be at least 1")
+	}
+	fromDay, err := ParseDateFrom(c.From)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	untilDay, err := ParseDateTo(c.Until)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	from, _ := time.ParseInLocation(dateLayout, fromDay, apiLocation)
+	until, _ := time.ParseInLocation(dateLayout, untilDay, apiLocation)
+	until = until.AddDate(0, 0, 1).Add(-time.Second)
+	if until.Before(from) {
+		return fmt.Errorf("--until %s is before --from %s", untilDay, fromDay)
+	}
+
+	defaults, err := loadDefaults(c.Defaults)
+	if err != nil {
+		return err
+	}
+	location, err := c.location(ctx, client)
+	if err != nil {
+		return err
+	}
+	events, err := readCalendar(ctx, client, c.Source, c.Timeout)
+	if err != nil {
+		return err
+	}
+
+	var results []icalResult
+	failed := 0
+	inFeed := map[string]bool{}
+	for i, e := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		inFeed[e.UID] = true
+		r := c.importEvent(ctx, client, e, from, until, location, defaults)
+		if r.Status == "failed" {
+			failed++
+		}
+		results = append(results, r)
+		fmt.Fprintf(os.Stderr, "Processed %d of %d events\r", i+1, len(events))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if c.Marker != "" && ctx.Err() == nil {
+		cancelled, err := c.cancelRemoved(ctx, client, inFeed, fromDay)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		for _, r := range cancelled {
+			if r.Status == "failed" {
+				failed++
+			}
+		}
+		results = append(results, cancelled...)
+	}
+
+	err = view[icalResult]{
+		Columns: icalColumns,
+		Rows:    results,
+		Empty:   "No events in the calendar.",
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return stopped(ctx, "after %d of %d events", len(results), len(events))
+	}
+	if c.DryRun {
+		fmt.Println("\nDry run: nothing was created, updated or cancelled.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d events failed", failed, len(results))
+	}
+	return nil
+}
+
+// importEvent creates or updates the event with the UID of e as externalid.
+func (c *EventsImportICalCmd) importEvent(ctx context.Context, client *feedfactory.Client, e *ical.Event, from, until time.Time, location map[string]interface{}, defaults map[string]interface{}) icalResult {
+	r := icalResult{UID: e.UID, Title: e.Summary}
+	fail := func(err error) icalResult {
+		r.Status = "failed"
+		r.Message = err.Error()
+		return r
+	}
+
+	occurrences, err := e.Occurrences(from, until, c.MaxDates)
+	if err != nil {
+		return fail(err)
+	}
+	dates := singleDates(e, occurrences, c.MaxDates)
+	r.Dates = len(dates)
+
+	id, err := findExisting(ctx, client, "events", "externalid", e.UID)
+	if err != nil {
+		return fail(err)
+	}
+	if len(dates) == 0 && id == "" {
+		r.Action = "skip"
+		r.Status = "ok"
+		r.Message = fmt.Sprintf("no dates between %s and %s", from.Format(dateLayout), until.Format(dateLayout))
+		return r
+	}
+
+	apply := func(doc map[string]interface{}) error {
+		type field struct {
+			path  string
+			value interface{}
+		}
+		details := "trcItemDetails[lang=" + c.Lang + "]"
+		fields := []field{
+			{"externalid", e.UID},
+			{details + ".title", e.Summary},
+			{"calendar.singleDates", withPastDates(doc, dates, from.Format(dateLayout))},
+			{"calendar.cancelled", e.Status == "CANCELLED"},
+		}
+		if e.Description != "" {
+			fields = append(fields, field{details + ".longdescription", e.Description})
+		}
+		switch {
+		case location != nil:
+			doc[docKey(doc, "location")] = location
+		case e.Location != "":
+			fields = append(fields, field{"location.label", e.Location})
+		}
+		for _, f := range fields {
+			path, err := parseDocPath(f.path)
+			if err != nil {
+				return err
+			}
+			if err := path.set(doc, f.value); err != nil {
+				return err
+			}
+		}
+		if c.Marker != "" {
+			updateMarkers(doc, []string{c.Marker}, nil)
+		}
+		return nil
+	}
+
+	if id == "" {
+		r.Action = "create"
+		if c.DryRun {
+			r.Status = "ok"
+			return r
+		}
+		doc := map[string]interface{}{}
+		if err := applyDefaults(doc, defaults); err != nil {
+			return fail(err)
+		}
+		if err := apply(doc); err != nil {
+			return fail(err)
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fail(err)
+		}
+		created, err := client.CreateResource(ctx, "events", data)
+		if err != nil {
+			return fail(err)
+		}
+		if r.ID, err = createdID(created); err != nil {
+			return fail(err)
+		}
+		r.Status = "ok"
+		return r
+	}
+
+	r.ID = id
+	raw, err := client.GetResource(ctx, "events", id)
+	if err != nil {
+		return fail(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fail(fmt.Errorf("parsing event %s: %w", id, err))
+	}
+	before, _ := json.Marshal(doc)
+	if err := apply(doc); err != nil {
+		return fail(err)
+	}
+	after, err := json.Marshal(doc)
+	if err != nil {
+		return fail(err)
+	}
+	r.Status = "ok"
+	if bytes.Equal(before, after) {
+		r.Action = "unchanged"
+		return r
+	}
+	r.Action = "update"
+	if c.DryRun {
+		return r
+	}
+	if err := client.UpdateResource(ctx, "events", id, after); err != nil {
+		return fail(err)
+	}
+	return r
+}
+
+// cancelRemoved marks the events with the feed's marker that are no longer in
+// the feed as cancelled. Only events with dates from fromDay on are cancelled:
+// feeds commonly drop events once they are over.
+func (c *EventsImportICalCmd) cancelRemoved(ctx context.Context, client *feedfactory.Client, inFeed map[string]bool, fromDay string) ([]icalResult, error) {
+	resources, err := fetchAllResources(ctx, client, "events", feedfactory.ListOptions{Markers: c.Marker}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing events with marker %s: %w", c.Marker, err)
+	}
+
+	var results []icalResult
+	for _, res := range resources {
+		if res.ExternalID == "" || inFeed[res.ExternalID] || !containsFold(res.GetMarkers(), c.Marker) {
+			continue
+		}
+		if res.Calendar == nil || res.Calendar.Cancelled || !hasDateFrom(res.Calendar, fromDay) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		r := icalResult{UID: res.ExternalID, Title: res.GetTitle(), Action: "cancel", ID: res.ID, Dates: len(res.Calendar.SingleDates), Status: "ok", Message: "no longer in the feed"}
+		if !c.DryRun {
+			err := client.ModifyResource(ctx, "events", res.ID, func(doc map[string]interface{}) error {
+				path, _ := parseDocPath("calendar.cancelled")
+				return path.set(doc, true)
+			})
+			if err != nil {
+				r.Status = "failed"
+				r.Message = err.Error()
+			}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// location returns the location block copied from --location-id, or nil.
+func (c *EventsImportICalCmd) location(ctx context.Context, client *feedfactory.Client) (map[string]interface{}, error) {
+	if c.LocationID == "" {
+		return nil, nil
+	}
+	raw, err := client.GetResource(ctx, "locations", c.LocationID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching location %s: %w", c.LocationID, err)
+	}
+	var loc feedfactory.Location
+	if err := json.Unmarshal(raw, &loc); err != nil {
+		return nil, fmt.Errorf("parsing location %s: %w", c.LocationID, err)
+	}
+	info := feedfactory.LocationInfo{Label: loc.GetTitle()}
+	if loc.Location != nil {
+		info.Address = loc.Location.Address
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	var block map[string]interface{}
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// singleDates turns occurrences into the singleDates of an event document, in
+// the API time zone. An all-day occurrence spanning several days gets a date
+// for each day.
+func singleDates(e *ical.Event, occurrences []ical.Occurrence, max int) []interface{} {
+	var dates []interface{}
+	for _, o := range occurrences {
+		start, end := o.Start.In(apiLocation), o.End.In(apiLocation)
+		if e.AllDay {
+			// All-day dates are floating: keep the calendar day as written.
+			start, end = o.Start, o.End
+			for d := start; d.Before(end) || d.Equal(start); d = d.AddDate(0, 0, 1) {
+				if len(dates) >= max {
+					return dates
+				}
+				dates = append(dates, map[string]interface{}{"date": d.Format(dateLayout)})
+			}
+			continue
+		}
+		if len(dates) >= max {
+			return dates
+		}
+		date := map[string]interface{}{
+			"date":      start.Format(dateLayout),
+			"starttime": start.Format("15:04"),
+		}
+		if end.After(start) {
+			date["endtime"] = end.Format("15:04")
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// withPastDates returns the single dates of doc before fromDay followed by
+// dates. The feed only replaces the dates from --from on, so an update keeps
+// the dates the event already had.
+func withPastDates(doc map[string]interface{}, dates []interface{}, fromDay string) []interface{} {
+	path, _ := parseDocPath("calendar.singleDates")
+	existing, _ := path.get(doc)
+	list, _ := existing.([]interface{})
+	var merged []interface{}
+	for _, d := range list {
+		date, _ := d.(map[string]interface{})
+		if day, _ := date[docKey(date, "date")].(string); day != "" && day[:min(len(day), 10)] < fromDay {
+			merged = append(merged, d)
+		}
+	}
+	return append(merged, dates...)
+}
+
+// hasDateFrom reports whether the calendar has a single date on or after day.
+func hasDateFrom(cal *feedfactory.Calendar, day string) bool {
+	for _, d := range cal.SingleDates {
+		if d.Date >= day {
+			return true
+		}
+	}
+	return false
+}
+
+// readCalendar reads the events of an iCalendar file or feed URL. webcal://
+// URLs are fetched over https with the HTTP settings of client, giving up after
+// timeout.
+func readCalendar(ctx context.Context, client *feedfactory.Client, source string, timeout time.Duration) ([]*ical.Event, error) {
+	var r io.Reader
+	if rest, ok := strings.CutPrefix(source, "webcal://"); ok {
+		source = "https://" + rest
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		resp, err := client.FetchURL(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", source, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", source, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("opening calendar: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	events, err := ical.Parse(r, apiLocation)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	return events, nil
+}
+
+// loadDefaults reads a YAML file of field values keyed by document path.
+func loadDefaults(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading defaults: %w", err)
+	}
+	var defaults map[string]interface{}
+	if err := yaml.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("parsing defaults %s: %w", path, err)
+	}
+	for p := range defaults {
+		if _, err := parseDocPath(p); err != nil {
+			return nil, fmt.Errorf("defaults %s: %w", path, err)
+		}
+	}
+	return defaults, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestWithPastDates(t *testing.T) {
+	doc := map[string]interface{}{
+		"calendar": map[string]interface{}{
+			"singleDates": []interface{}{
+				map[string]interface{}{"date": "2026-03-06T00:00:00", "when": []interface{}{}},
+				map[string]interface{}{"date": "2026-03-13"},
+				map[string]interface{}{"Date": "2026-03-20"},
+				map[string]interface{}{"date": "2026-03-27"},
+				map[string]interface{}{"when": []interface{}{}},
+			},
+		},
+	}
+	feed := []interface{}{
+		map[string]interface{}{"date": "2026-03-20"},
+		map[string]interface{}{"date": "2026-04-03"},
+	}
+	want := []interface{}{
+		map[string]interface{}{"date": "2026-03-06T00:00:00", "when": []interface{}{}},
+		map[string]interface{}{"date": "2026-03-13"},
+		map[string]interface{}{"date": "2026-03-20"},
+		map[string]interface{}{"date": "2026-04-03"},
+	}
+	if got := withPastDates(doc, feed, "2026-03-20"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	if got := withPastDates(map[string]interface{}{}, feed, "2026-03-20"); !reflect.DeepEqual(got, feed) {
+		t.Errorf("new event: got %v, want the feed dates", got)
+	}
+}
+
+func TestReadCalendarURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "tff-test" {
+			t.Errorf("User-Agent %q", ua)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("API token sent to the feed: %q", auth)
+		}
+		if r.URL.Path == "/slow.ics" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:e1\r\nSUMMARY:Concert\r\nDTSTART:20260601T180000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithUserAgent("tff-test"), feedfactory.WithToken("secret"))
+
+	events, err := readCalendar(context.Background(), client, srv.URL+"/agenda.ics", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].UID != "e1" {
+		t.Errorf("events %+v", events)
+	}
+
+	if _, err := readCalendar(context.Background(), client, srv.URL+"/slow.ics", 50*time.Millisecond); err == nil {
+		t.Error("expected a timeout")
+	}
+}
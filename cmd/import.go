@@ -0,0 +1,562 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+type ResourceImportCmd[K resourceKind] struct {
+	File    string `arg:"" type:"existingfile" help:"CSV or Excel (.xlsx) file with a header row. CSV files may use commas or semicolons."`
+	Mapping string `required:"" type:"existingfile" help:"YAML file that maps spreadsheet columns to document paths, e.g. trcItemDetails[lang=nl].title or location.address.city. See the README for the format."`
+	Sheet   string `help:"Sheet to read from an Excel file. Default: the first sheet."`
+	DryRun  bool   `name:"dry-run" help:"Validate the rows and look up existing ${plural} without creating or updating anything."`
+	Kind    K      `embed:""`
+}
+
+// importMapping is the mapping file of an import.
+type importMapping struct {
+	// Match is the field that identifies existing resources: externalid or
+	// trcid. Rows with a known value update that resource; other rows create
+	// one. Without Match every row creates a resource.
+	Match string `yaml:"match"`
+	// Defaults are set on created resources before the row values, by path.
+	Defaults map[string]interface{} `yaml:"defaults"`
+	Columns  []importColumn         `yaml:"columns"`
+}
+
+// importColumn maps a spreadsheet column to a document path.
+type importColumn struct {
+	Column   string `yaml:"column"`
+	Path     string `yaml:"path"`
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+	// Values lists the allowed values, if limited.
+	Values []string `yaml:"values"`
+	// Separator splits list values. Default: a comma.
+	Separator string `yaml:"separator"`
+	// Key turns each item of a list into an object with the item in this
+	// field, e.g. catid for category types.
+	Key string `yaml:"key"`
+
+	path  docPath
+	index int
+}
+
+// importTypes are the value types of mapped columns.
+var importTypes = []string{"text", "number", "bool", "date", "time", "list"}
+
+// importResult is the outcome of importing one row.
+type importResult struct {
+	Row     int    `json:"row"`
+	Action  string `json:"action"`
+	ID      string `json:"id,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+var importColumns = []column[importResult]{
+	{Name: "row", Value: func(r importResult) string { return strconv.Itoa(r.Row) }},
+	{Name: "action", Value: func(r importResult) string { return r.Action }},
+	{Name: "id", Value: func(r importResult) string { return r.ID }},
+	{Name: "status", Value: func(r importResult) string { return r.Status }},
+	{Name: "message", Width: 80, Value: func(r importResult) string { return r.Message }},
+}
+
+func (c *ResourceImportCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	m, err := loadImportMapping(c.Mapping)
+	if err != nil {
+		return err
+	}
+	header, rows, err := readTable(c.File, c.Sheet)
+	if err != nil {
+		return err
+	}
+	if err := m.bind(header, c.File); err != nil {
+		return err
+	}
+
+	var results []importResult
+	failed := 0
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		r := m.importRow(ctx, client, d, row, c.DryRun)
+		if r.Status == "invalid" || r.Status == "failed" {
+			failed++
+		}
+		results = append(results, r)
+		fmt.Fprintf(os.Stderr, "Processed %d of %d rows\r", i+1, len(rows))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	// A row interrupted halfway reports an error that isn't its own.
+	if ctx.Err() != nil && len(results) > 0 && results[len(results)-1].Status == "failed" {
+		results = results[:len(results)-1]
+		failed--
+	}
+
+	err = view[importResult]{
+		Columns: importColumns,
+		Rows:    results,
+		Empty:   "No rows to import.",
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return stopped(ctx, "after %d of %d rows", len(results), len(rows))
+	}
+	if c.DryRun {
+		fmt.Println("\nDry run: nothing was created or updated.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", failed, len(rows))
+	}
+	return nil
+}
+
+func loadImportMapping(path string) (*importMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping: %w", err)
+	}
+	var m importMapping
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing mapping %s: %w", path, err)
+	}
+
+	if len(m.Columns) == 0 {
+		return nil, fmt.Errorf("mapping %s: no columns", path)
+	}
+	m.Match = strings.ToLower(m.Match)
+	if m.Match != "" && m.Match != "externalid" && m.Match != "trcid" {
+		return nil, fmt.Errorf("mapping %s: match must be externalid or trcid", path)
+	}
+	matched := m.Match == ""
+	for i := range m.Columns {
+		col := &m.Columns[i]
+		if col.Column == "" {
+			return nil, fmt.Errorf("mapping %s: column %d has no name", path, i+1)
+		}
+		if col.path, err = parseDocPath(col.Path); err != nil {
+			return nil, fmt.Errorf("mapping %s: column %q: %w", path, col.Column, err)
+		}
+		if col.Type == "" {
+			col.Type = "text"
+		}
+		if !contains(importTypes, col.Type) {
+			return nil, fmt.Errorf("mapping %s: column %q: unknown type %q (use %s)", path, col.Column, col.Type, strings.Join(importTypes, ", "))
+		}
+		if col.Separator == "" {
+			col.Separator = ","
+		}
+		if strings.EqualFold(col.path.String(), m.Match) {
+			matched = true
+		}
+	}
+	if !matched {
+		return nil, fmt.Errorf("mapping %s: match is %s, but no column maps to it", path, m.Match)
+	}
+	for p := range m.Defaults {
+		if _, err := parseDocPath(p); err != nil {
+			return nil, fmt.Errorf("mapping %s: defaults: %w", path, err)
+		}
+	}
+	return &m, nil
+}
+
+// bind finds the mapped columns in the header of the file.
+func (m *importMapping) bind(header []string, file string) error {
+	for i := range m.Columns {
+		col := &m.Columns[i]
+		col.index = -1
+		for j, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(col.Column)) {
+				col.index = j
+				break
+			}
+		}
+		if col.index < 0 {
+			return fmt.Errorf("column %q not found in %s (columns: %s)", col.Column, filepath.Base(file), strings.Join(header, ", "))
+		}
+	}
+	return nil
+}
+
+// tableRow is a data row of the input file with its row number in the file.
+type tableRow struct {
+	line   int
+	values []string
+}
+
+// importRow validates, matches and imports one row.
+func (m *importMapping) importRow(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, row tableRow, dryRun bool) importResult {
+	r := importResult{Row: row.line}
+	fail := func(err error) importResult {
+		r.Status = "failed"
+		r.Message = err.Error()
+		return r
+	}
+
+	values, problems := m.convert(row.values)
+	if len(problems) > 0 {
+		r.Status = "invalid"
+		r.Message = strings.Join(problems, "; ")
+		return r
+	}
+
+	r.Action = "create"
+	if m.Match != "" {
+		key := values[m.Match]
+		if key != nil {
+			id, err := findExisting(ctx, client, d.Endpoint, m.Match, fmt.Sprint(key))
+			if err != nil {
+				return fail(err)
+			}
+			if id != "" {
+				r.Action = "update"
+				r.ID = id
+			}
+		}
+	}
+
+	if dryRun {
+		r.Status = "ok"
+		return r
+	}
+
+	if r.Action == "update" {
+		err := client.ModifyResource(ctx, d.Endpoint, r.ID, func(doc map[string]interface{}) error {
+			return m.apply(doc, values)
+		})
+		if err != nil {
+			return fail(err)
+		}
+		r.Status = "ok"
+		return r
+	}
+
+	doc := map[string]interface{}{}
+	if err := applyDefaults(doc, m.Defaults); err != nil {
+		return fail(err)
+	}
+	if err := m.apply(doc, values); err != nil {
+		return fail(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fail(err)
+	}
+	created, err := client.CreateResource(ctx, d.Endpoint, data)
+	if err != nil {
+		return fail(err)
+	}
+	if r.ID, err = createdID(created); err != nil {
+		return fail(err)
+	}
+	r.Status = "ok"
+	return r
+}
+
+// convert checks and converts the mapped values of a row, keyed by path. Empty
+// cells are left out, so they don't clear fields of existing resources.
+func (m *importMapping) convert(row []string) (map[string]interface{}, []string) {
+	values := map[string]interface{}{}
+	var problems []string
+	for _, col := range m.Columns {
+		cell := ""
+		if col.index < len(row) {
+			cell = strings.TrimSpace(row[col.index])
+		}
+		if cell == "" {
+			if col.Required {
+				problems = append(problems, fmt.Sprintf("%s is required", col.Column))
+			}
+			continue
+		}
+		v, err := col.convert(cell)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", col.Column, err))
+			continue
+		}
+		values[strings.ToLower(col.path.String())] = v
+	}
+	return values, problems
+}
+
+// convert converts a non-empty cell to the column's type.
+func (col *importColumn) convert(cell string) (interface{}, error) {
+	if col.Type != "list" {
+		if err := col.allowed(cell); err != nil {
+			return nil, err
+		}
+	}
+	switch col.Type {
+	case "number":
+		f, err := strconv.ParseFloat(strings.Replace(cell, ",", ".", 1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", cell)
+		}
+		return f, nil
+	case "bool":
+		switch strings.ToLower(cell) {
+		case "true", "yes", "ja", "y", "j", "x", "1":
+			return true, nil
+		case "false", "no", "nee", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q is not yes or no", cell)
+	case "date":
+		return parseImportDate(cell)
+	case "time":
+		return parseImportTime(cell)
+	case "list":
+		var items []interface{}
+		for _, item := range strings.Split(cell, col.Separator) {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if err := col.allowed(item); err != nil {
+				return nil, err
+			}
+			if col.Key != "" {
+				items = append(items, map[string]interface{}{col.Key: item})
+			} else {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+	return cell, nil
+}
+
+// allowed checks a value against the column's allowed values.
+func (col *importColumn) allowed(v string) error {
+	if len(col.Values) > 0 && !containsFold(col.Values, v) {
+		return fmt.Errorf("%q is not one of %s", v, strings.Join(col.Values, ", "))
+	}
+	return nil
+}
+
+// importDateLayouts are the date formats accepted in date columns. Dates with
+// the day first are read the Dutch way (dd-mm-yyyy).
+var importDateLayouts = []string{"2006-01-02", "02-01-2006", "2-1-2006", "02/01/2006", "2/1/2006", "02.01.2006", "2.1.2006"}
+
+// parseImportDate parses a date cell, or the serial number Excel stores for a
+// date, and returns it as yyyy-mm-dd.
+func parseImportDate(cell string) (string, error) {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, cell); err == nil {
+			return t.Format(dateLayout), nil
+		}
+	}
+	// Excel serials count days from 1900; 2958465 is 9999-12-31.
+	if serial, err := strconv.ParseFloat(cell, 64); err == nil {
+		if serial > 0 && serial <= 2958465 {
+			if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+				return t.Format(dateLayout), nil
+			}
+		}
+		return "", fmt.Errorf("%q is not a date (use yyyy-mm-dd or dd-mm-yyyy)", cell)
+	}
+	// Timestamps keep only their date.
+	if t, err := feedfactory.ParseTimestamp(cell); err == nil {
+		return t.In(apiLocation).Format(dateLayout), nil
+	}
+	return "", fmt.Errorf("%q is not a date (use yyyy-mm-dd or dd-mm-yyyy)", cell)
+}
+
+// parseImportTime parses a time cell such as 9:30, 09.30 or an Excel time
+// fraction, and returns it as hh:mm.
+func parseImportTime(cell string) (string, error) {
+	for _, layout := range []string{"15:04", "15.04", "15:04:05"} {
+		if t, err := time.Parse(layout, cell); err == nil {
+			return t.Format("15:04"), nil
+		}
+	}
+	if f, err := strconv.ParseFloat(cell, 64); err == nil && f >= 0 && f < 1 {
+		minutes := int(f*24*60 + 0.5)
+		return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60), nil
+	}
+	return "", fmt.Errorf("%q is not a time (use hh:mm)", cell)
+}
+
+// applyDefaults sets defaults, keyed by document path, on a new document in
+// path order.
+func applyDefaults(doc map[string]interface{}, defaults map[string]interface{}) error {
+	paths := make([]string, 0, len(defaults))
+	for p := range defaults {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		path, err := parseDocPath(p)
+		if err != nil {
+			return err
+		}
+		if err := path.set(doc, defaults[p]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply sets the converted values of a row on doc, in mapping order. A list
+// replaces a comma-separated string (as markers may be stored) by a string.
+func (m *importMapping) apply(doc map[string]interface{}, values map[string]interface{}) error {
+	for _, col := range m.Columns {
+		if v, ok := values[strings.ToLower(col.path.String())]; ok {
+			if old, _ := col.path.get(doc); col.Type == "list" && col.Key == "" {
+				if _, isString := old.(string); isString {
+					v = joinList(v.([]interface{}))
+				}
+			}
+			if err := col.path.set(doc, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findExisting returns the ID of the resource whose externalid or trcid is key,
+// or "" if there is none.
+func findExisting(ctx context.Context, client *feedfactory.Client, endpoint, field, key string) (string, error) {
+	opts := feedfactory.ListOptions{Size: 10}
+	if field == "trcid" {
+		opts.TRCID = key
+	} else {
+		opts.ExternalID = key
+	}
+	result, err := client.ListResources(ctx, endpoint, opts, nil)
+	if err != nil {
+		return "", fmt.Errorf("looking up %s %s: %w", field, key, err)
+	}
+	resources, err := feedfactory.ParseResources(result.Results)
+	if err != nil {
+		return "", err
+	}
+	// The filter may match partially; only an exact match counts.
+	var ids []string
+	for _, r := range resources {
+		if (field == "trcid" && r.TRCID == key) || (field == "externalid" && r.ExternalID == key) {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) > 1 {
+		return "", fmt.Errorf("%s %s matches more than one resource", field, key)
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return ids[0], nil
+}
+
+// readTable reads the header and data rows of a CSV or Excel file. Empty rows
+// are skipped.
+func readTable(path, sheet string) ([]string, []tableRow, error) {
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm":
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+		if sheet == "" {
+			sheet = f.GetSheetName(0)
+		}
+		// Raw values, so dates arrive as serial numbers instead of in the
+		// display format of the cell.
+		records, err = f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	case ".csv", ".txt":
+		if sheet != "" {
+			return nil, nil, fmt.Errorf("--sheet only applies to Excel files")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		r := csv.NewReader(bytes.NewReader(data))
+		r.Comma = csvDelimiter(data)
+		r.FieldsPerRecord = -1
+		if records, err = r.ReadAll(); err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%s: only .csv and .xlsx files can be imported", filepath.Base(path))
+	}
+
+	var header []string
+	var rows []tableRow
+	for i, record := range records {
+		if isEmptyRecord(record) {
+			continue
+		}
+		if header == nil {
+			header = record
+			continue
+		}
+		rows = append(rows, tableRow{line: i + 1, values: record})
+	}
+	if header == nil {
+		return nil, nil, fmt.Errorf("%s is empty", filepath.Base(path))
+	}
+	return header, rows, nil
+}
+
+// csvDelimiter guesses the delimiter of a CSV file from its first line:
+// semicolons, as written by Excel in Dutch locales, or commas.
+func csvDelimiter(data []byte) rune {
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func joinList(items []interface{}) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, ",")
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+	"github.com/TheFeedFactory/tff-cli/internal/lint"
+)
+
+type LintCmd struct {
+	Types     []string `arg:"" optional:"" help:"Resource types to lint: events, locations, routes, venues, eventgroups. Default: all types."`
+	Rules     string   `type:"path" help:"Lint config file (YAML) that enables, disables or re-grades rules. Default: .tff-lint.yaml, then ~/.config/tff-cli/lint.yaml."`
+	FailOn    string   `name:"fail-on" enum:"info,warning,error,never" default:"error" help:"Exit with a non-zero status when an issue of at least this severity is found. Use 'never' to always exit 0. Default: error."`
+	ListRules bool     `name:"list-rules" help:"List all rules with their effective severity and whether they are enabled, then exit."`
+	JSON      bool     `short:"j" help:"Output issues as JSON."`
+	ScanFlags
+}
+
+func (c *LintCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	cfg, err := lint.LoadConfig(c.Rules)
+	if err != nil {
+		return err
+	}
+
+	if c.ListRules {
+		return printLintRules(cfg)
+	}
+
+	types, err := c.scanTypes(c.Types)
+	if err != nil {
+		return err
+	}
+
+	lintCtx := &lint.Context{Now: time.Now()}
+	linter, err := lint.New(cfg, lintCtx)
+	if err != nil {
+		return err
+	}
+
+	if linter.Enabled("deprecated-category") {
+		data, err := client.GetOntology(ctx)
+		if err != nil {
+			return fmt.Errorf("loading ontology: %w", err)
+		}
+		ontology, err := feedfactory.ParseOntology(data)
+		if err != nil {
+			return err
+		}
+		lintCtx.DeprecatedCategories = ontology.DeprecatedIDs()
+	}
+
+	var issues []lint.Issue
+	scanned := 0
+	for _, t := range types {
+		resources, err := c.scan(ctx, client, t)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", t, err)
+		}
+		scanned += len(resources)
+		for i := range resources {
+			issues = append(issues, linter.Lint(t, &resources[i])...)
+		}
+	}
+	lint.SortIssues(issues)
+
+	counts := map[lint.Severity]int{}
+	for _, is := range issues {
+		counts[is.Severity]++
+	}
+
+	if jsonOutput(c.JSON) {
+		if issues == nil {
+			issues = []lint.Issue{}
+		}
+		if err := printJSON(issues); err != nil {
+			return err
+		}
+	} else if len(issues) == 0 {
+		fmt.Printf("No issues found in %d resources.\n", scanned)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEVERITY\tRULE\tTYPE\tID\tTITLE\tMESSAGE")
+		fmt.Fprintln(w, "--------\t----\t----\t--\t-----\t-------")
+		for _, is := range issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				is.Severity, is.Rule, is.Type, is.ID, truncate(is.Title, 30), is.Message)
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d resources checked: %d errors, %d warnings, %d info\n",
+			scanned, counts[lint.Error], counts[lint.Warning], counts[lint.Info])
+	}
+
+	if c.FailOn == "never" {
+		return nil
+	}
+	threshold, err := lint.ParseSeverity(c.FailOn)
+	if err != nil {
+		return err
+	}
+	failing := 0
+	for sev, n := range counts {
+		if sev >= threshold {
+			failing += n
+		}
+	}
+	if failing > 0 {
+		return fmt.Errorf("lint found %d issues of severity %s or higher", failing, threshold)
+	}
+	return nil
+}
+
+func printLintRules(cfg *lint.Config) error {
+	rules, err := lint.Configure(cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSEVERITY\tENABLED\tTYPES\tDESCRIPTION")
+	fmt.Fprintln(w, "----\t--------\t-------\t-----\t-----------")
+	for _, r := range rules {
+		types := "all"
+		if len(r.Types) > 0 {
+			types = strings.Join(r.Types, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ID, r.Severity, boolYesNo(r.Check != nil), types, r.Description)
+	}
+	w.Flush()
+	return nil
+}
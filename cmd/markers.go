@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// updateMarkers adds and removes markers on a raw resource document and reports
+// whether anything changed. The API stores markers either as an array or as a
+// comma-separated string; the existing representation is kept.
+func updateMarkers(doc map[string]interface{}, add, remove []string) bool {
+	key := docKey(doc, "markers")
+	var current []string
+	asString := false
+	switch v := doc[key].(type) {
+	case string:
+		asString = true
+		current = splitList(v)
+	case []interface{}:
+		for _, m := range v {
+			if s, ok := m.(string); ok && s != "" {
+				current = append(current, s)
+			}
+		}
+	}
+
+	removeSet := map[string]bool{}
+	for _, m := range remove {
+		removeSet[strings.ToLower(m)] = true
+	}
+
+	changed := false
+	present := map[string]bool{}
+	var next []string
+	for _, m := range current {
+		if removeSet[strings.ToLower(m)] {
+			changed = true
+			continue
+		}
+		present[strings.ToLower(m)] = true
+		next = append(next, m)
+	}
+	for _, m := range add {
+		if !present[strings.ToLower(m)] {
+			present[strings.ToLower(m)] = true
+			next = append(next, m)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false
+	}
+	if asString {
+		doc[key] = strings.Join(next, ",")
+	} else {
+		list := make([]interface{}, len(next))
+		for i, m := range next {
+			list[i] = m
+		}
+		doc[key] = list
+	}
+	return true
+}
+
+// updateKeywords adds and removes keywords on a raw resource document and
+// reports whether anything changed. Keywords are stored as objects with an id,
+// label and value, as strings, or as a comma-separated string; added keywords
+// follow the existing representation. A removed name matches any of the id,
+// label and value of a keyword.
+func updateKeywords(doc map[string]interface{}, add []dictionaryEntry, remove []string) bool {
+	key := docKey(doc, "keywords")
+	if s, ok := doc[key].(string); ok {
+		var names []string
+		for _, e := range add {
+			names = append(names, e.Name)
+		}
+		next := map[string]interface{}{"markers": s}
+		changed := updateMarkers(next, names, remove)
+		doc[key] = next["markers"]
+		return changed
+	}
+
+	current, _ := doc[key].([]interface{})
+	removeSet := map[string]bool{}
+	for _, name := range remove {
+		removeSet[strings.ToLower(name)] = true
+	}
+
+	// New keywords are objects, like those of the API, unless the document
+	// has plain strings.
+	objects := true
+	if len(current) > 0 {
+		_, objects = current[0].(map[string]interface{})
+	}
+
+	changed := false
+	present := map[string]bool{}
+	var next []interface{}
+	for _, k := range current {
+		names := keywordNames(k)
+		removed := false
+		for _, n := range names {
+			if removeSet[strings.ToLower(n)] {
+				removed = true
+			}
+		}
+		if removed {
+			changed = true
+			continue
+		}
+		for _, n := range names {
+			present[strings.ToLower(n)] = true
+		}
+		next = append(next, k)
+	}
+	for _, e := range add {
+		if present[strings.ToLower(e.Name)] {
+			continue
+		}
+		present[strings.ToLower(e.Name)] = true
+		switch {
+		case !objects:
+			next = append(next, e.Name)
+		case e.Object != nil:
+			next = append(next, e.Object)
+		default:
+			next = append(next, map[string]interface{}{"label": e.Name, "value": e.Name})
+		}
+		changed = true
+	}
+
+	if changed {
+		if next == nil {
+			next = []interface{}{}
+		}
+		doc[key] = next
+	}
+	return changed
+}
+
+// keywordNames returns the names a keyword of a document is known by.
+func keywordNames(k interface{}) []string {
+	switch v := k.(type) {
+	case string:
+		return []string{v}
+	case map[string]interface{}:
+		var names []string
+		for _, field := range []string{"value", "label", "id"} {
+			if s, ok := v[field].(string); ok && s != "" {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// docLabels returns the names of the markers or keywords in a document field.
+func docLabels(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return splitList(v)
+	case []interface{}:
+		var names []string
+		for _, k := range v {
+			if n := keywordNames(k); len(n) > 0 {
+				names = append(names, n[0])
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// dictionaryEntry is a keyword or marker from an account dictionary.
+type dictionaryEntry struct {
+	// Name is the value stored on resources.
+	Name string
+	// Names are all the names the entry is known by.
+	Names []string
+	// Object is the entry itself when the dictionary holds objects.
+	Object map[string]interface{}
+}
+
+// parseDictionary reads a keyword or marker dictionary: a list of strings, or
+// of objects with a value, label, name or id.
+func parseDictionary(data []byte) ([]dictionaryEntry, error) {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		var s string
+		if json.Unmarshal(data, &s) != nil {
+			return nil, fmt.Errorf("unexpected dictionary format")
+		}
+		for _, name := range splitList(s) {
+			items = append(items, name)
+		}
+	}
+
+	var entries []dictionaryEntry
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			if v != "" {
+				entries = append(entries, dictionaryEntry{Name: v, Names: []string{v}})
+			}
+		case map[string]interface{}:
+			e := dictionaryEntry{Object: v}
+			for _, field := range []string{"value", "label", "name", "id"} {
+				if s, ok := v[field].(string); ok && s != "" {
+					e.Names = append(e.Names, s)
+				}
+			}
+			if len(e.Names) > 0 {
+				e.Name = e.Names[0]
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// lookupEntry returns the entry known by name, compared case-insensitively.
+func lookupEntry(entries []dictionaryEntry, name string) (dictionaryEntry, bool) {
+	for _, e := range entries {
+		if containsFold(e.Names, name) {
+			return e, true
+		}
+	}
+	return dictionaryEntry{}, false
+}
+
+// labelFlags are the arguments of mark and tag.
+type labelFlags struct {
+	Args     []string `arg:"" optional:"" passthrough:"" help:"IDs of the ${plural}, followed by the changes: +name adds, -name removes (e.g. 123 456 +featured -archived). Without IDs the changes apply to the ${plural} matching --where; put -- before a first change that removes (--where markers=x -- -featured)."`
+	Where    []string `sep:"none" help:"Select ${plural} with a list filter instead of IDs, as key=value. Repeatable. Keys as for translate --where, e.g. --where markers=summer --where wfstatus=approved."`
+	AllowNew bool     `name:"allow-new" help:"Allow names that are not in the account's dictionary."`
+	DryRun   bool     `name:"dry-run" help:"Show what would change without saving anything."`
+	Force    bool     `short:"f" help:"Change the ${plural} matched by --where without a confirmation prompt."`
+}
+
+// labelResult is the outcome of changing the markers or keywords of one resource.
+type labelResult struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Status  string   `json:"status"`
+	Labels  []string `json:"labels"`
+	Message string   `json:"message,omitempty"`
+}
+
+// labelKind is what mark and tag change.
+type labelKind struct {
+	// Name is the field and dictionary: markers or keywords.
+	Name       string
+	dictionary func(ctx context.Context, client *feedfactory.Client, resourceType string) (json.RawMessage, error)
+	update     func(doc map[string]interface{}, add []dictionaryEntry, remove []string) bool
+}
+
+var markerLabels = labelKind{
+	Name: "markers",
+	dictionary: func(ctx context.Context, client *feedfactory.Client, resourceType string) (json.RawMessage, error) {
+		return client.GetMarkers(ctx, resourceType)
+	},
+	update: func(doc map[string]interface{}, add []dictionaryEntry, remove []string) bool {
+		names := make([]string, len(add))
+		for i, e := range add {
+			names[i] = e.Name
+		}
+		return updateMarkers(doc, names, remove)
+	},
+}
+
+var keywordLabels = labelKind{
+	Name: "keywords",
+	dictionary: func(ctx context.Context, client *feedfactory.Client, resourceType string) (json.RawMessage, error) {
+		return client.GetKeywords(ctx, resourceType)
+	},
+	update: updateKeywords,
+}
+
+// parseArgs takes the flags that follow the IDs out of the arguments, since
+// kong leaves everything after the first ID to the command, and splits the
+// rest into IDs and changes. Kong reads a -name before the first argument as
+// flags, so without IDs a removal needs -- in front of it.
+func (f *labelFlags) parseArgs() (ids, add, remove []string, err error) {
+	for i := 0; i < len(f.Args); i++ {
+		arg := f.Args[i]
+		switch {
+		case arg == "--":
+		case arg == "--allow-new":
+			f.AllowNew = true
+		case arg == "--dry-run":
+			f.DryRun = true
+		case arg == "--force" || arg == "-f":
+			f.Force = true
+		case arg == "--where":
+			if i+1 == len(f.Args) {
+				return nil, nil, nil, fmt.Errorf("--where needs a key=value filter")
+			}
+			i++
+			f.Where = append(f.Where, f.Args[i])
+		case strings.HasPrefix(arg, "--where="):
+			f.Where = append(f.Where, strings.TrimPrefix(arg, "--where="))
+		case strings.HasPrefix(arg, "+") && len(arg) > 1:
+			add = append(add, arg[1:])
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !strings.HasPrefix(arg, "--"):
+			remove = append(remove, arg[1:])
+		case strings.HasPrefix(arg, "--"):
+			return nil, nil, nil, fmt.Errorf("unknown flag %s", arg)
+		default:
+			ids = append(ids, arg)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, nil, fmt.Errorf("no changes: give +name to add or -name to remove")
+	}
+	return ids, add, remove, nil
+}
+
+func (f *labelFlags) run(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, kind labelKind) error {
+	ids, addNames, remove, err := f.parseArgs()
+	if err != nil {
+		return err
+	}
+
+	// Only added names are checked: removing one that left the dictionary
+	// is how resources are cleaned up.
+	var entries []dictionaryEntry
+	if len(addNames) > 0 {
+		data, err := kind.dictionary(ctx, client, d.Dictionary)
+		if err == nil {
+			entries, err = parseDictionary(data)
+		}
+		if err != nil && !f.AllowNew {
+			return fmt.Errorf("reading the %s dictionary: %w (use --allow-new to skip the check)", kind.Name, err)
+		}
+	}
+	var add []dictionaryEntry
+	var unknown []string
+	for _, name := range addNames {
+		if e, ok := lookupEntry(entries, name); ok {
+			add = append(add, e)
+			continue
+		}
+		if !f.AllowNew {
+			unknown = append(unknown, name)
+		}
+		add = append(add, dictionaryEntry{Name: name, Names: []string{name}})
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("not in the %s dictionary of %s: %s (use --allow-new to add them anyway)", kind.Name, d.Plural, strings.Join(unknown, ", "))
+	}
+
+	targets, err := resolveTargets(ctx, client, d.Endpoint, ids, f.Where)
+	if err != nil {
+		return err
+	}
+	if len(f.Where) > 0 && !f.Force && !f.DryRun {
+		if !confirm(fmt.Sprintf("Change the %s of %d %s?", kind.Name, len(targets), pluralize(d.Singular, len(targets)))) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	var results []labelResult
+	updated, failed := 0, 0
+	for _, id := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+		r := f.change(ctx, client, d, kind, id, add, remove)
+		switch r.Status {
+		case "updated", "would update":
+			updated++
+		case "failed":
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	err = view[labelResult]{
+		Columns: []column[labelResult]{
+			{Name: "id", Value: func(r labelResult) string { return r.ID }},
+			{Name: "title", Width: 40, Value: func(r labelResult) string { return r.Title }},
+			{Name: "status", Value: func(r labelResult) string { return r.Status }},
+			{Name: kind.Name, Width: 60, Value: func(r labelResult) string { return strings.Join(r.Labels, ", ") }},
+			{Name: "message", Width: 60, Value: func(r labelResult) string { return r.Message }},
+		},
+		Rows: results,
+	}.render(false)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return stopped(ctx, "after %d of %d %s: updated %d", len(results), len(targets), d.Plural, updated)
+	}
+	if f.DryRun {
+		fmt.Println("\nDry run: nothing was saved.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, len(targets), d.Plural)
+	}
+	return nil
+}
+
+// change applies the changes to one resource and saves it if anything changed.
+func (f *labelFlags) change(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, kind labelKind, id string, add []dictionaryEntry, remove []string) labelResult {
+	r := labelResult{ID: id}
+	fail := func(err error) labelResult {
+		r.Status = "failed"
+		r.Message = err.Error()
+		return r
+	}
+
+	body, err := client.GetResource(ctx, d.Endpoint, id)
+	if err != nil {
+		return fail(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fail(fmt.Errorf("parsing %s %s: %w", d.Singular, id, err))
+	}
+	if !kind.update(doc, add, remove) {
+		r.Status = "unchanged"
+	} else {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fail(err)
+		}
+		body = data
+		if f.DryRun {
+			r.Status = "would update"
+		} else {
+			if err := client.UpdateResource(ctx, d.Endpoint, id, data); err != nil {
+				return fail(err)
+			}
+			r.Status = "updated"
+		}
+	}
+
+	var res feedfactory.Resource
+	if json.Unmarshal(body, &res) == nil {
+		r.Title = res.GetTitle()
+	}
+	r.Labels = docLabels(doc[docKey(doc, kind.Name)])
+	return r
+}
+
+type ResourceMarkCmd[K resourceKind] struct {
+	labelFlags
+}
+
+func (c *ResourceMarkCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	return c.run(ctx, client, describe[K](), markerLabels)
+}
+
+type ResourceTagCmd[K resourceKind] struct {
+	labelFlags
+}
+
+func (c *ResourceTagCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	return c.run(ctx, client, describe[K](), keywordLabels)
+}
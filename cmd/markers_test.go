@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateMarkers(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         map[string]interface{}
+		add, remove []string
+		want        map[string]interface{}
+		changed     bool
+	}{
+		{
+			name: "array",
+			doc:  map[string]interface{}{"markers": []interface{}{"zomer", "Featured"}},
+			add:  []string{"featured", "gratis"}, remove: []string{"ZOMER"},
+			want:    map[string]interface{}{"markers": []interface{}{"Featured", "gratis"}},
+			changed: true,
+		},
+		{
+			name:    "comma-separated string",
+			doc:     map[string]interface{}{"markers": "zomer,featured"},
+			add:     []string{"gratis"},
+			want:    map[string]interface{}{"markers": "zomer,featured,gratis"},
+			changed: true,
+		},
+		{
+			name:    "key in another case",
+			doc:     map[string]interface{}{"Markers": "zomer"},
+			remove:  []string{"zomer"},
+			want:    map[string]interface{}{"Markers": ""},
+			changed: true,
+		},
+		{
+			name: "unchanged",
+			doc:  map[string]interface{}{"markers": []interface{}{"zomer"}},
+			add:  []string{"Zomer"}, remove: []string{"winter"},
+			want: map[string]interface{}{"markers": []interface{}{"zomer"}},
+		},
+		{
+			name:    "no markers yet",
+			doc:     map[string]interface{}{},
+			add:     []string{"zomer"},
+			want:    map[string]interface{}{"markers": []interface{}{"zomer"}},
+			changed: true,
+		},
+	}
+	for _, tt := range tests {
+		if changed := updateMarkers(tt.doc, tt.add, tt.remove); changed != tt.changed || !reflect.DeepEqual(tt.doc, tt.want) {
+			t.Errorf("%s: changed %v, doc %v; want %v, %v", tt.name, changed, tt.doc, tt.changed, tt.want)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type ResourceMediaCmd[K resourceKind] struct {
+	ID mediaTarget[K] `arg:"" help:"ID of the ${singular}."`
+}
+
+// mediaTarget is the resource whose media the subcommands of 'media' work on.
+// Kong passes it to their Run methods.
+type mediaTarget[K resourceKind] struct {
+	ID      string             `arg:"" help:"ID of the ${singular}."`
+	List    MediaListCmd[K]    `cmd:"" help:"List the media of the ${singular}, numbered in their order."`
+	Add     MediaAddCmd[K]     `cmd:"" help:"Add an image by URL. The image is downloaded and checked for its format, dimensions and file size first. When the ${singular} has no main image yet, the new image becomes the main image."`
+	Remove  MediaRemoveCmd[K]  `cmd:"" help:"Remove media items, by number (see list) or URL."`
+	SetMain MediaSetMainCmd[K] `cmd:"" name:"set-main" help:"Make a media item the main image, by number or URL."`
+	Reorder MediaReorderCmd[K] `cmd:"" help:"Move media items to the front in the given order, by number or URL. The others follow in their current order."`
+}
+
+// mediaEditFlags are the flags of the 'media' commands that change the media.
+type mediaEditFlags struct {
+	DryRun bool `name:"dry-run" help:"Show the new media without saving them."`
+	Force  bool `short:"f" help:"Save without a confirmation prompt."`
+}
+
+// mediaRow is a media item in the output of 'media', with the change an edit
+// makes to it in the preview.
+type mediaRow struct {
+	n      int
+	media  feedfactory.Media
+	change string
+}
+
+var mediaColumns = []column[mediaRow]{
+	{Name: "#", Value: func(r mediaRow) string {
+		if r.n == 0 {
+			return "-"
+		}
+		return strconv.Itoa(r.n)
+	}},
+	{Name: "main", Value: func(r mediaRow) string {
+		if r.media.Main {
+			return "yes"
+		}
+		return ""
+	}},
+	{Name: "type", Value: func(r mediaRow) string { return r.media.MediaType }},
+	{Name: "title", Width: 30, Value: func(r mediaRow) string { return r.media.Title }},
+	{Name: "url", Width: 60, Value: func(r mediaRow) string { return r.media.URL }},
+}
+
+var mediaChangeColumn = column[mediaRow]{Name: "change", Value: func(r mediaRow) string { return r.change }}
+
+type MediaListCmd[K resourceKind] struct {
+	JSON bool `short:"j" help:"Output JSON instead of a table."`
+}
+
+func (c *MediaListCmd[K]) Run(ctx context.Context, client *feedfactory.Client, t *mediaTarget[K]) error {
+	d := describe[K]()
+	r, err := getResourceDoc(ctx, client, d, t.ID)
+	if err != nil {
+		return err
+	}
+	rows := make([]mediaRow, len(r.Media))
+	for i, m := range r.Media {
+		rows[i] = mediaRow{n: i + 1, media: m}
+	}
+	media := r.Media
+	if media == nil {
+		media = []feedfactory.Media{}
+	}
+	return view[mediaRow]{
+		Columns:  mediaColumns,
+		Rows:     rows,
+		Doc:      func(r mediaRow) interface{} { return r.media },
+		Document: media,
+		Empty:    fmt.Sprintf("The %s has no media.", d.Singular),
+	}.render(c.JSON)
+}
+
+// MediaAddCmd adds an image by URL. It sets the single title of a media item
+// only: captions per language are not supported, because the API
+// documentation doesn't say where a media item keeps them, and a guessed field
+// could be dropped by the server or shown as the wrong text.
+type MediaAddCmd[K resourceKind] struct {
+	Image     string        `arg:"" help:"URL of the image, or a local image file to check."`
+	Main      bool          `help:"Make the image the main image."`
+	Title     string        `help:"Title of the image. Captions per language are not supported."`
+	Type      string        `default:"photo" help:"Media type of the item. Default: photo."`
+	NoCheck   bool          `name:"no-check" help:"Add a URL without downloading and checking the image."`
+	MinWidth  int           `name:"min-width" default:"800" help:"Minimum image width in pixels. Default: 800."`
+	MinHeight int           `name:"min-height" default:"600" help:"Minimum image height in pixels. Default: 600."`
+	MaxSize   int           `name:"max-size" default:"10" help:"Maximum file size in MB. Default: 10."`
+	Timeout   time.Duration `name:"request-timeout" default:"30s" help:"Timeout for downloading the image. Default: 30s."`
+	mediaEditFlags
+}
+
+func (c *MediaAddCmd[K]) Run(ctx context.Context, client *feedfactory.Client, t *mediaTarget[K]) error {
+	item := feedfactory.Media{URL: c.Image, MediaType: c.Type, Title: c.Title, Main: c.Main}
+
+	remote := strings.HasPrefix(c.Image, "http://") || strings.HasPrefix(c.Image, "https://")
+	if !remote || !c.NoCheck {
+		info, err := c.check(ctx, client, remote)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s, %dx%d, %s.\n", c.Image, strings.ToUpper(info.format), info.width, info.height, formatBytes(info.size))
+	}
+	if !remote {
+		// The API has no endpoint for uploading files, so local images can
+		// only be checked.
+		return fmt.Errorf("%s is a local file and the FeedFactory API has no file upload; put the image online and add its URL", c.Image)
+	}
+
+	return c.edit(ctx, client, describe[K](), t.ID, func(media []mediaItem) ([]mediaItem, error) {
+		for _, m := range media {
+			if m.URL == item.URL {
+				return nil, fmt.Errorf("the %s already has %s", describe[K]().Singular, item.URL)
+			}
+		}
+		if !hasMainImage(media) {
+			item.Main = true
+		}
+		if item.Main {
+			for i := range media {
+				media[i].Main = false
+			}
+		}
+		return append(media, mediaItem{Media: item, old: -1}), nil
+	})
+}
+
+// imageInfo describes a checked image.
+type imageInfo struct {
+	format        string
+	width, height int
+	size          int64
+}
+
+// check reads the image and checks it against the limits of the flags. Remote
+// images are fetched with the HTTP client and User-Agent of the API client.
+func (c *MediaAddCmd[K]) check(ctx context.Context, client *feedfactory.Client, remote bool) (*imageInfo, error) {
+	var r io.Reader
+	if remote {
+		ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+		resp, err := client.FetchURL(ctx, c.Image)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", c.Image, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", c.Image, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(c.Image)
+		if err != nil {
+			return nil, fmt.Errorf("opening image: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	maxSize := int64(c.MaxSize) << 20
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", c.Image, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%s is larger than %d MB (--max-size)", c.Image, c.MaxSize)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a JPEG, PNG or GIF image", c.Image)
+	}
+	info := &imageInfo{format: format, width: cfg.Width, height: cfg.Height, size: int64(len(data))}
+	if info.width < c.MinWidth || info.height < c.MinHeight {
+		return nil, fmt.Errorf("%s is %dx%d pixels, smaller than %dx%d (--min-width, --min-height)", c.Image, info.width, info.height, c.MinWidth, c.MinHeight)
+	}
+	return info, nil
+}
+
+type MediaRemoveCmd[K resourceKind] struct {
+	Items []string `arg:"" help:"Numbers (see list) or URLs of the media items to remove."`
+	mediaEditFlags
+}
+
+func (c *MediaRemoveCmd[K]) Run(ctx context.Context, client *feedfactory.Client, t *mediaTarget[K]) error {
+	return c.edit(ctx, client, describe[K](), t.ID, func(media []mediaItem) ([]mediaItem, error) {
+		remove := map[int]bool{}
+		for _, ref := range c.Items {
+			i, err := findMedia(media, ref)
+			if err != nil {
+				return nil, err
+			}
+			remove[i] = true
+		}
+		var kept []mediaItem
+		for i, m := range media {
+			if !remove[i] {
+				kept = append(kept, m)
+			}
+		}
+		return kept, nil
+	})
+}
+
+type MediaSetMainCmd[K resourceKind] struct {
+	Item string `arg:"" help:"Number (see list) or URL of the media item."`
+	mediaEditFlags
+}
+
+func (c *MediaSetMainCmd[K]) Run(ctx context.Context, client *feedfactory.Client, t *mediaTarget[K]) error {
+	return c.edit(ctx, client, describe[K](), t.ID, func(media []mediaItem) ([]mediaItem, error) {
+		main, err := findMedia(media, c.Item)
+		if err != nil {
+			return nil, err
+		}
+		for i := range media {
+			media[i].Main = i == main
+		}
+		return media, nil
+	})
+}
+
+type MediaReorderCmd[K resourceKind] struct {
+	Items []string `arg:"" help:"Numbers (see list) or URLs of the media items to put first, in order."`
+	mediaEditFlags
+}
+
+func (c *MediaReorderCmd[K]) Run(ctx context.Context, client *feedfactory.Client, t *mediaTarget[K]) error {
+	return c.edit(ctx, client, describe[K](), t.ID, func(media []mediaItem) ([]mediaItem, error) {
+		var order []mediaItem
+		moved := map[int]bool{}
+		for _, ref := range c.Items {
+			i, err := findMedia(media, ref)
+			if err != nil {
+				return nil, err
+			}
+			if moved[i] {
+				return nil, fmt.Errorf("media item %s is given twice", ref)
+			}
+			moved[i] = true
+			order = append(order, media[i])
+		}
+		for i, m := range media {
+			if !moved[i] {
+				order = append(order, m)
+			}
+		}
+		return order, nil
+	})
+}
+
+// mediaItem is a media item being edited, with its index in the saved media,
+// or -1 for a new item. The index, not the URL, identifies the item, as a
+// resource can have the same URL twice.
+type mediaItem struct {
+	feedfactory.Media
+	old int
+}
+
+// findMedia returns the index of the media item ref refers to, by its number
+// in the list (from 1) or its URL. Of items with the same URL the first is
+// found; use numbers for the others.
+func findMedia(media []mediaItem, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(media) {
+			return 0, fmt.Errorf("no media item %d (there are %d)", n, len(media))
+		}
+		return n - 1, nil
+	}
+	for i, m := range media {
+		if m.URL == ref {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no media item with URL %s", ref)
+}
+
+func hasMainImage(media []mediaItem) bool {
+	for _, m := range media {
+		if m.Main {
+			return true
+		}
+	}
+	return false
+}
+
+// edit changes the media of a resource with fn, shows the result and saves it
+// after confirmation.
+func (f *mediaEditFlags) edit(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, id string, fn func(media []mediaItem) ([]mediaItem, error)) error {
+	r, err := getResourceDoc(ctx, client, d, id)
+	if err != nil {
+		return err
+	}
+	items := make([]mediaItem, len(r.Media))
+	for i, m := range r.Media {
+		items[i] = mediaItem{Media: m, old: i}
+	}
+	items, err = fn(items)
+	if err != nil {
+		return err
+	}
+	before := r.Media
+	media := make([]feedfactory.Media, len(items))
+	for i, m := range items {
+		media[i] = m.Media
+	}
+	r.Media = media
+
+	rows, changed := mediaChanges(before, items)
+	if !changed {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	fmt.Printf("New media of %s %s:\n\n", d.Singular, id)
+	err = view[mediaRow]{
+		Columns: append(append([]column[mediaRow](nil), mediaColumns...), mediaChangeColumn),
+		Rows:    rows,
+		Empty:   fmt.Sprintf("The %s has no media.", d.Singular),
+	}.render(false)
+	if err != nil {
+		return err
+	}
+	if !hasMainImage(items) && len(items) > 0 {
+		fmt.Println("\nNo media item is the main image.")
+	}
+
+	if f.DryRun {
+		fmt.Println("\nDry run: the media were not saved.")
+		return nil
+	}
+	if !f.Force && !confirm(fmt.Sprintf("\nSave the new media of %s %s?", d.Singular, id)) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	doc, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := client.UpdateResource(ctx, d.Endpoint, id, doc); err != nil {
+		return fmt.Errorf("saving media: %w", err)
+	}
+	n := len(media)
+	fmt.Printf("Saved the media of %s %s (%d %s).\n", d.Singular, id, n, pluralize("item", n))
+	return nil
+}
+
+// mediaChanges numbers the items of after and marks the ones that are new,
+// moved or became or stopped being the main image, followed by the items of
+// before that were removed. It reports whether anything changed.
+func mediaChanges(before []feedfactory.Media, after []mediaItem) ([]mediaRow, bool) {
+	// Items have moved when their order among the items that were kept
+	// changed, not when the ones before them were removed or added.
+	kept := map[int]bool{}
+	for _, m := range after {
+		if m.old >= 0 {
+			kept[m.old] = true
+		}
+	}
+	oldRank := map[int]int{}
+	for i := range before {
+		if kept[i] {
+			oldRank[i] = len(oldRank)
+		}
+	}
+
+	changed := len(before) != len(after)
+	rows := make([]mediaRow, 0, len(after))
+	newRank := 0
+	for i, m := range after {
+		row := mediaRow{n: i + 1, media: m.Media}
+		switch {
+		case m.old < 0:
+			row.change = "added"
+		case m.Main != before[m.old].Main && m.Main:
+			row.change = "main"
+		case m.Main != before[m.old].Main:
+			row.change = "not main"
+		case oldRank[m.old] != newRank:
+			row.change = "moved"
+		}
+		if m.old >= 0 {
+			newRank++
+		}
+		changed = changed || row.change != ""
+		rows = append(rows, row)
+	}
+	for i, m := range before {
+		if !kept[i] {
+			rows = append(rows, mediaRow{media: m, change: "removed"})
+		}
+	}
+	return rows, changed
+}
+
+// getResourceDoc fetches a resource of any type as a Resource, which keeps
+// the fields it doesn't declare for saving it again.
+func getResourceDoc(ctx context.Context, client *feedfactory.Client, d resourceDescriptor, id string) (*feedfactory.Resource, error) {
+	raw, err := client.GetResource(ctx, d.Endpoint, id)
+	if err != nil {
+		return nil, err
+	}
+	var r feedfactory.Resource
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("parsing %s %s: %w", d.Singular, id, err)
+	}
+	return &r, nil
+}
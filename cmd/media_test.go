@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestMediaChanges(t *testing.T) {
+	// The first and third item have the same URL.
+	before := []feedfactory.Media{
+		{URL: "https://example.nl/a.jpg", Main: true},
+		{URL: "https://example.nl/b.jpg"},
+		{URL: "https://example.nl/a.jpg", Title: "copy"},
+	}
+	item := func(old int) mediaItem { return mediaItem{Media: before[old], old: old} }
+	tests := []struct {
+		name    string
+		after   []mediaItem
+		want    []string
+		changed bool
+	}{
+		{"unchanged", []mediaItem{item(0), item(1), item(2)}, []string{"", "", ""}, false},
+		{"remove the duplicate", []mediaItem{item(0), item(1)}, []string{"", "", "removed"}, true},
+		{"remove the first of two", []mediaItem{item(1), item(2)}, []string{"", "", "removed"}, true},
+		{"swap", []mediaItem{item(0), item(2), item(1)}, []string{"", "moved", "moved"}, true},
+		{"add", []mediaItem{item(0), item(1), item(2), {Media: feedfactory.Media{URL: "https://example.nl/c.jpg"}, old: -1}}, []string{"", "", "", "added"}, true},
+	}
+	for _, tt := range tests {
+		rows, changed := mediaChanges(before, tt.after)
+		var got []string
+		for _, r := range rows {
+			got = append(got, r.change)
+		}
+		if !reflect.DeepEqual(got, tt.want) || changed != tt.changed {
+			t.Errorf("%s: changes %q, changed %v; want %q, %v", tt.name, got, changed, tt.want, tt.changed)
+		}
+	}
+
+	rows, _ := mediaChanges(before, []mediaItem{item(1), item(2)})
+	if removed := rows[2].media; removed.Title != "" || !removed.Main {
+		t.Errorf("removed %+v, want the first item", removed)
+	}
+
+	main := item(2)
+	main.Main = true
+	first := item(0)
+	first.Main = false
+	rows, _ = mediaChanges(before, []mediaItem{first, item(1), main})
+	if rows[0].change != "not main" || rows[2].change != "main" {
+		t.Errorf("set-main on the duplicate: %q, %q", rows[0].change, rows[2].change)
+	}
+}
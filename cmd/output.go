@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+var outputFormats = []string{"table", "wide", "json", "yaml", "csv", "ndjson", "ids"}
+
+// outputOpts holds the global --output and --columns flags.
+var outputOpts = struct {
+	Format  string
+	Columns []string
+}{Format: "table"}
+
+// SetOutput sets the global output format and column selection. An empty format
+// keeps the default table output.
+func SetOutput(format string, columns []string) error {
+	if format == "" {
+		format = "table"
+	}
+	format = strings.ToLower(format)
+	valid := false
+	for _, f := range outputFormats {
+		if f == format {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid output format %q (use %s)", format, strings.Join(outputFormats, ", "))
+	}
+	outputOpts.Format = format
+	outputOpts.Columns = nil
+	for _, c := range columns {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			outputOpts.Columns = append(outputOpts.Columns, c)
+		}
+	}
+	return nil
+}
+
+// outputFormat returns the format to use for a command, honouring its -j flag as
+// a shorthand for --output json. A --query switches table output to JSON.
+func outputFormat(jsonFlag bool) string {
+	if jsonFlag || (querying() && (outputOpts.Format == "table" || outputOpts.Format == "wide")) {
+		return "json"
+	}
+	return outputOpts.Format
+}
+
+// jsonOutput reports whether a command that only has table and JSON output
+// should print JSON.
+func jsonOutput(jsonFlag bool) bool {
+	return outputFormat(jsonFlag) == "json"
+}
+
+// column is one field of a rendered row.
+type column[T any] struct {
+	// Name is the header (upper-cased) and the key used with --columns.
+	Name string
+	// Wide columns are only shown with --output wide or csv, or when selected.
+	Wide bool
+	// Width truncates the value in table output; 0 means no limit.
+	Width int
+	Value func(T) string
+}
+
+// view describes how a command's result is shown in each output format.
+type view[T any] struct {
+	Columns []column[T]
+	Rows    []T
+	// Doc returns the document encoded for a row in json, yaml and ndjson output.
+	// When nil, the row itself is encoded.
+	Doc func(T) interface{}
+	// Document, if set, is the complete document printed for json and yaml output
+	// (e.g. a search result with paging information) instead of the list of rows.
+	Document interface{}
+	// Text, if set, replaces the column layout in table and wide output, for
+	// detail views that don't fit in columns.
+	Text func() error
+	// Empty is printed instead of an empty table.
+	Empty string
+	// Footer is printed after table and wide output.
+	Footer string
+}
+
+// render writes the view in the selected output format.
+func (v view[T]) render(jsonFlag bool) error {
+	format := outputFormat(jsonFlag)
+
+	cols, err := v.selectColumns(format)
+	if err != nil {
+		return err
+	}
+	// An explicit column selection also narrows structured output.
+	projected := len(outputOpts.Columns) > 0
+
+	doc := func(row T) interface{} {
+		if projected {
+			m := make(map[string]string, len(cols))
+			for _, c := range cols {
+				m[c.Name] = c.Value(row)
+			}
+			return m
+		}
+		if v.Doc != nil {
+			return v.Doc(row)
+		}
+		return row
+	}
+	docs := func() []interface{} {
+		out := make([]interface{}, len(v.Rows))
+		for i, row := range v.Rows {
+			out[i] = doc(row)
+		}
+		return out
+	}
+
+	switch format {
+	case "json":
+		if v.Document != nil && !projected {
+			return printJSON(v.Document)
+		}
+		return printJSON(docs())
+	case "yaml":
+		if v.Document != nil && !projected {
+			return printYAML(v.Document)
+		}
+		return printYAML(docs())
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		for _, d := range docs() {
+			if querying() {
+				if err := printQuery(d, "ndjson"); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := enc.Encode(normalizeDoc(d)); err != nil {
+				return fmt.Errorf("encoding JSON: %w", err)
+			}
+		}
+		return nil
+	case "ids":
+		id := v.column("id")
+		if id == nil {
+			return fmt.Errorf("--output ids is not supported by this command")
+		}
+		for _, row := range v.Rows {
+			fmt.Println(id.Value(row))
+		}
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = c.Name
+		}
+		w.Write(header)
+		for _, row := range v.Rows {
+			record := make([]string, len(cols))
+			for i, c := range cols {
+				record[i] = c.Value(row)
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if v.Text != nil && !projected {
+		return v.Text()
+	}
+	if len(v.Rows) == 0 && v.Empty != "" {
+		fmt.Println(v.Empty)
+		return nil
+	}
+
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c.Name)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	fmt.Fprintln(w, strings.Join(underline(header), "\t"))
+	for _, row := range v.Rows {
+		values := make([]string, len(cols))
+		for i, c := range cols {
+			values[i] = c.Value(row)
+			if c.Width > 0 && format == "table" {
+				values[i] = truncate(values[i], c.Width)
+			}
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+
+	if v.Footer != "" {
+		fmt.Printf("\n%s\n", v.Footer)
+	}
+	return nil
+}
+
+// selectColumns returns the columns to show: those named with --columns, all
+// columns for wide and csv output, or the narrow set otherwise.
+func (v view[T]) selectColumns(format string) ([]column[T], error) {
+	if len(outputOpts.Columns) > 0 {
+		var cols []column[T]
+		for _, name := range outputOpts.Columns {
+			c := v.column(name)
+			if c == nil {
+				return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(v.columnNames(), ", "))
+			}
+			cols = append(cols, *c)
+		}
+		return cols, nil
+	}
+
+	var cols []column[T]
+	for _, c := range v.Columns {
+		if !c.Wide || format == "wide" || format == "csv" {
+			cols = append(cols, c)
+		}
+	}
+	return cols, nil
+}
+
+func (v view[T]) column(name string) *column[T] {
+	for i := range v.Columns {
+		if v.Columns[i].Name == name {
+			return &v.Columns[i]
+		}
+	}
+	return nil
+}
+
+func (v view[T]) columnNames() []string {
+	names := make([]string, len(v.Columns))
+	for i, c := range v.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// rawDoc wraps a JSON document from the API so it is re-encoded as-is, keeping
+// field order and exact numbers.
+func rawDoc(raw []byte) interface{} {
+	if !json.Valid(raw) {
+		return string(raw)
+	}
+	return json.RawMessage(raw)
+}
+
+// normalizeDoc turns a value into plain maps, slices and scalars by round-tripping
+// it through JSON, so YAML output uses the same field names as JSON output.
+func normalizeDoc(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return v
+	}
+	return out
+}
+
+func printYAML(v interface{}) error {
+	if querying() {
+		return printQuery(v, "yaml")
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlValue(normalizeDoc(v))); err != nil {
+		return fmt.Errorf("encoding YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// yamlValue converts json.Number values so they are written as YAML numbers
+// rather than quoted strings.
+func yamlValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if !strings.ContainsAny(t.String(), ".eE") {
+			// Too big for int64: write the digits as they are.
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: t.String()}
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = yamlValue(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = yamlValue(e)
+		}
+		return t
+	}
+	return v
+}
+
+// genericColumns derives columns from a list of JSON objects or strings, for API
+// responses without a fixed layout (dictionaries, accounts). Objects get one
+// column per scalar field, with id first.
+func genericColumns(rows []interface{}) []column[interface{}] {
+	keys := map[string]bool{}
+	scalarOnly := true
+	for _, row := range rows {
+		m, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scalarOnly = false
+		for k, v := range m {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				keys[k] = true
+			}
+		}
+	}
+	if scalarOnly {
+		return []column[interface{}]{{Name: "value", Value: func(row interface{}) string { return scalarString(row) }}}
+	}
+
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == "id") != (names[j] == "id") {
+			return names[i] == "id"
+		}
+		return names[i] < names[j]
+	})
+
+	cols := make([]column[interface{}], len(names))
+	for i, name := range names {
+		key := name
+		cols[i] = column[interface{}]{Name: strings.ToLower(key), Width: 50, Value: func(row interface{}) string {
+			m, _ := row.(map[string]interface{})
+			return scalarString(m[key])
+		}}
+	}
+	return cols
+}
+
+// genericRows returns the list inside a JSON response: the document itself when
+// it is an array, the first array field of an object, or the object as one row.
+func genericRows(data []byte) ([]interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		return t, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if list, ok := t[k].([]interface{}); ok {
+				return list, nil
+			}
+		}
+		return []interface{}{t}, nil
+	}
+	return []interface{}{v}, nil
+}
+
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return boolYesNo(t)
+	case float64:
+		return fmt.Sprint(t)
+	}
+	return fmt.Sprint(v)
+}
+
+// renderGeneric renders a JSON list response without a fixed layout. Table
+// output lists the scalar fields of each entry.
+func renderGeneric(data []byte, jsonFlag bool, empty string) error {
+	rows, err := genericRows(data)
+	if err != nil {
+		return printRawJSON(data)
+	}
+	return view[interface{}]{
+		Columns:  genericColumns(rows),
+		Rows:     rows,
+		Document: rawDoc(data),
+		Empty:    empty,
+	}.render(jsonFlag)
+}
+
+// renderGenericObject renders a single JSON object. Table output is the
+// indented JSON document.
+func renderGenericObject(data []byte, jsonFlag bool) error {
+	doc := rawDoc(data)
+	return view[interface{}]{
+		Columns:  genericColumns([]interface{}{doc}),
+		Rows:     []interface{}{doc},
+		Document: doc,
+		Text:     func() error { return printRawJSON(data) },
+	}.render(jsonFlag)
+}
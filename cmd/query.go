@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/itchyny/gojq"
+	"gopkg.in/yaml.v3"
+)
+
+// queryOpts holds the global --query and --raw flags.
+var queryOpts struct {
+	code *gojq.Code
+	raw  bool
+}
+
+// SetQuery compiles the jq expression applied to all JSON output. raw prints
+// string results without quotes, like jq -r.
+func SetQuery(expr string, raw bool) error {
+	queryOpts.raw = raw
+	if expr == "" {
+		return nil
+	}
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+	queryOpts.code = code
+	return nil
+}
+
+// querying reports whether a --query expression is set.
+func querying() bool {
+	return queryOpts.code != nil
+}
+
+// printQuery runs the --query expression on v and prints each result in format:
+// indented JSON, one compact JSON document per line (ndjson) or YAML. With --raw,
+// strings are printed as-is.
+func printQuery(v interface{}, format string) error {
+	input, err := jqValue(v)
+	if err != nil {
+		return err
+	}
+
+	iter := queryOpts.code.Run(input)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := result.(error); ok {
+			if err, ok := err.(*gojq.HaltError); ok && err.Value() == nil {
+				return nil
+			}
+			return fmt.Errorf("--query: %w", err)
+		}
+		if s, ok := result.(string); ok && queryOpts.raw {
+			fmt.Println(s)
+			continue
+		}
+		if err := printQueryResult(result, format); err != nil {
+			return err
+		}
+	}
+}
+
+func printQueryResult(v interface{}, format string) error {
+	if format == "yaml" {
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encoding YAML: %w", err)
+		}
+		return enc.Close()
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	if format != "ndjson" {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// jqValue converts v into the plain values gojq works on. Integers are kept as
+// integers (big ones as *big.Int) so long numeric IDs aren't rounded.
+func jqValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	return convertNumbers(out), nil
+}
+
+func convertNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			if i == int64(int(i)) {
+				return int(i)
+			}
+		}
+		if n, ok := new(big.Int).SetString(t.String(), 10); ok {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = convertNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = convertNumbers(e)
+		}
+	}
+	return v
+}
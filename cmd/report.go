@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type ReportCmd struct {
+	Translations ReportTranslationsCmd `cmd:"" help:"Report resources that lack a title, short description or long description in required languages, grouped by resource type and organisation. Lists the IDs of items that need fixing."`
+	Workbook     ReportWorkbookCmd     `cmd:"" help:"Write an Excel workbook with all resources matching the filters: a summary sheet with counts per type and workflow status, then one sheet per resource type or per status. Columns follow --columns; dates are real date cells and website and image URLs are clickable."`
+}
+
+// translationFields maps the --require values to the TRCItemDetail fields they check.
+var translationFields = map[string]func(d *feedfactory.TRCItemDetail) string{
+	"title":            func(d *feedfactory.TRCItemDetail) string { return d.Title },
+	"shortdescription": func(d *feedfactory.TRCItemDetail) string { return d.ShortDescription },
+	"longdescription":  func(d *feedfactory.TRCItemDetail) string { return d.LongDescription },
+}
+
+type ReportTranslationsCmd struct {
+	Types   []string `arg:"" optional:"" help:"Resource types to scan: events, locations, routes, venues, eventgroups. Default: all types."`
+	Langs   string   `default:"en,de" help:"Comma-separated languages every item must have content in. Default: en,de."`
+	Require string   `default:"title,shortdescription,longdescription" help:"Comma-separated fields that must be filled in each required language: title, shortdescription, longdescription. Default: all three."`
+	GroupBy string   `name:"group-by" enum:"userorganisation,owner" default:"userorganisation" help:"Group the summary by user organisation (default) or by owner."`
+	IDs     bool     `name:"ids" help:"Only print the IDs of items with missing translations, one per line."`
+	JSON    bool     `short:"j" help:"Output the full report as JSON."`
+	ScanFlags
+}
+
+// translationGap describes one resource with missing translated content.
+type translationGap struct {
+	Type    string   `json:"type"`
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Group   string   `json:"group"`
+	Missing []string `json:"missing"`
+}
+
+type translationSummary struct {
+	Type       string         `json:"type"`
+	Group      string         `json:"group"`
+	Items      int            `json:"items"`
+	Incomplete int            `json:"incomplete"`
+	MissingBy  map[string]int `json:"missingByLanguage"`
+}
+
+func (c *ReportTranslationsCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	langs := splitList(c.Langs)
+	if len(langs) == 0 {
+		return fmt.Errorf("--langs must name at least one language")
+	}
+	fields := splitList(c.Require)
+	for _, f := range fields {
+		if _, ok := translationFields[f]; !ok {
+			return fmt.Errorf("--require: unknown field %q (use title, shortdescription, longdescription)", f)
+		}
+	}
+
+	types, err := c.scanTypes(c.Types)
+	if err != nil {
+		return err
+	}
+
+	var gaps []translationGap
+	var summaries []*translationSummary
+	for _, t := range types {
+		resources, err := c.scan(ctx, client, t)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", t, err)
+		}
+
+		var typeSummaries []*translationSummary
+		byGroup := map[string]*translationSummary{}
+		for _, r := range resources {
+			group := r.UserOrg
+			if c.GroupBy == "owner" {
+				group = r.Owner
+			}
+			if group == "" {
+				group = "(none)"
+			}
+			s, ok := byGroup[group]
+			if !ok {
+				s = &translationSummary{Type: t, Group: group, MissingBy: map[string]int{}}
+				byGroup[group] = s
+				typeSummaries = append(typeSummaries, s)
+			}
+			s.Items++
+
+			missing := missingTranslations(r, langs, fields)
+			if len(missing) == 0 {
+				continue
+			}
+			s.Incomplete++
+			seen := map[string]bool{}
+			for _, m := range missing {
+				lang := strings.SplitN(m, ":", 2)[0]
+				if !seen[lang] {
+					seen[lang] = true
+					s.MissingBy[lang]++
+				}
+			}
+			gaps = append(gaps, translationGap{Type: t, ID: r.ID, Title: r.GetTitle(), Group: group, Missing: missing})
+		}
+
+		sort.Slice(typeSummaries, func(i, j int) bool { return typeSummaries[i].Group < typeSummaries[j].Group })
+		summaries = append(summaries, typeSummaries...)
+	}
+
+	if jsonOutput(c.JSON) {
+		return printJSON(map[string]interface{}{
+			"languages": langs,
+			"fields":    fields,
+			"summary":   summaries,
+			"items":     gaps,
+		})
+	}
+
+	if c.IDs {
+		for _, g := range gaps {
+			fmt.Println(g.ID)
+		}
+		return nil
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := []string{"TYPE", strings.ToUpper(c.GroupBy), "ITEMS", "INCOMPLETE"}
+	for _, l := range langs {
+		header = append(header, "MISSING "+strings.ToUpper(l))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	fmt.Fprintln(w, strings.Join(underline(header), "\t"))
+	for _, s := range summaries {
+		row := []string{s.Type, truncate(s.Group, 30), fmt.Sprint(s.Items), fmt.Sprint(s.Incomplete)}
+		for _, l := range langs {
+			row = append(row, fmt.Sprint(s.MissingBy[l]))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	if len(gaps) == 0 {
+		fmt.Printf("\nAll items have %s in %s.\n", strings.Join(fields, ", "), strings.Join(langs, ", "))
+		return nil
+	}
+
+	fmt.Println("\nItems to fix:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tID\tTITLE\tMISSING")
+	fmt.Fprintln(w, "----\t--\t-----\t-------")
+	for _, g := range gaps {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", g.Type, g.ID, truncate(g.Title, 40), strings.Join(g.Missing, ", "))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d items with missing translations\n", len(gaps))
+	return nil
+}
+
+// missingTranslations returns the "lang:field" pairs that are empty on r.
+func missingTranslations(r feedfactory.Resource, langs, fields []string) []string {
+	var missing []string
+	for _, lang := range langs {
+		d := r.Detail(lang)
+		for _, f := range fields {
+			if d == nil || strings.TrimSpace(translationFields[f](d)) == "" {
+				missing = append(missing, lang+":"+f)
+			}
+		}
+	}
+	return missing
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// resourceKind is a resource type (events, locations, ...). Each type is a struct
+// whose fields are its type-specific list and export filters, usually none. The
+// generic commands below are instantiated per type and embed the kind, so kong
+// adds those filters as flags.
+//
+// Registering a type takes a kind with a descriptor and a field in Resources,
+// which sets the singular and plural variables used in help texts.
+type resourceKind interface {
+	descriptor() resourceDescriptor
+	// params returns the API query parameters of the type-specific filters.
+	params() (url.Values, error)
+}
+
+// resourceDescriptor describes a resource type for the generic commands.
+type resourceDescriptor struct {
+	// Endpoint is the API path and the name used on the command line: events.
+	Endpoint string
+	// Dictionary is the type name of the account's keyword and marker
+	// dictionaries: eventGroup.
+	Dictionary string
+	// Singular and Plural name the type in messages: event group, event groups.
+	Singular string
+	Plural   string
+	// Label heads the detail view of 'get': Event Group.
+	Label string
+	// Columns are the table columns of 'list'.
+	Columns []column[resourceRow]
+	// ExportFormats are the export formats the API supports besides excel.
+	ExportFormats []string
+	// DateParams are the query parameters of the first and last day of the
+	// type's date filter, used to split chunked exports into date windows.
+	// Empty for types without a date filter.
+	DateParams [2]string
+}
+
+// Resources are the commands of all resource types, embedded in the CLI. The
+// order of the fields is the order scanning commands process the types in.
+type Resources struct {
+	Events      EventsCmd      `cmd:"" set:"singular=event" set:"plural=events" help:"Manage events (list, get, export, import, import-ical, clone, mark, tag, categorize, media, dates, groups, delete, publish, unpublish, comments, revisions)."`
+	Locations   LocationsCmd   `cmd:"" set:"singular=location" set:"plural=locations" help:"Manage locations (list, get, export, import, clone, mark, tag, categorize, media, delete, publish, unpublish, comments, revisions)."`
+	Routes      RoutesCmd      `cmd:"" set:"singular=route" set:"plural=routes" help:"Manage routes (list, get, export, import, clone, mark, tag, categorize, media, delete, publish, unpublish, comments, revisions)."`
+	Venues      VenuesCmd      `cmd:"" set:"singular=venue" set:"plural=venues" help:"Manage venues (list, get, export, import, clone, mark, tag, categorize, media, delete, publish, unpublish, comments, revisions)."`
+	EventGroups EventGroupsCmd `cmd:"" name:"eventgroups" set:"singular=event group" set:"plural=event groups" help:"Manage event groups (list, get, export, import, clone, mark, tag, categorize, media, members, delete, publish, unpublish, comments, revisions)."`
+}
+
+// resourceKinds lists the descriptors of the types in Resources.
+var resourceKinds = kindsOf(reflect.TypeOf(Resources{}))
+
+// kindCommand is implemented by ResourceCmd, and so by every command that
+// embeds it.
+type kindCommand interface {
+	kindDescriptor() resourceDescriptor
+}
+
+func (ResourceCmd[K]) kindDescriptor() resourceDescriptor { return describe[K]() }
+
+func kindsOf(t reflect.Type) []resourceDescriptor {
+	kinds := make([]resourceDescriptor, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		k, ok := reflect.Zero(t.Field(i).Type).Interface().(kindCommand)
+		if !ok {
+			panic(fmt.Sprintf("Resources.%s is not a resource command", t.Field(i).Name))
+		}
+		kinds = append(kinds, k.kindDescriptor())
+	}
+	return kinds
+}
+
+// descriptorFor returns the descriptor of the resource type with the given endpoint.
+func descriptorFor(endpoint string) resourceDescriptor {
+	for _, d := range resourceKinds {
+		if d.Endpoint == endpoint {
+			return d
+		}
+	}
+	return resourceDescriptor{Endpoint: endpoint, Singular: endpoint, Plural: endpoint, Label: endpoint}
+}
+
+// noFilters is embedded by kinds without type-specific filters.
+type noFilters struct{}
+
+func (noFilters) params() (url.Values, error) { return nil, nil }
+
+// exportChecker is implemented by kinds with extra requirements for an export format.
+type exportChecker interface {
+	checkExport(format string) error
+}
+
+func describe[K resourceKind]() resourceDescriptor {
+	var k K
+	return k.descriptor()
+}
+
+type ResourceCmd[K resourceKind] struct {
+	List       ResourceListCmd[K]       `cmd:"" help:"List and search ${plural}. Supports full-text search, workflow status filtering, markers, keywords, and more. Returns paginated results sorted by last modified date by default."`
+	Get        ResourceGetCmd[K]        `cmd:"" help:"Get detailed information about the ${singular} with the given ID. Returns all fields including title, description, location, media, and metadata."`
+	Export     ResourceExportCmd[K]     `cmd:"" help:"Export ${plural} to an Excel (.xlsx) file. Supports all the same filters as 'list'. The API generates the Excel file server-side with all resource fields included; with --chunked the file is built client-side from parallel list requests, for result sets too large for one export."`
+	Delete     ResourceDeleteCmd[K]     `cmd:"" help:"Delete the ${singular} with the given ID. This sets its workflow status to deleted."`
+	Publish    ResourcePublishCmd[K]    `cmd:"" help:"Publish the ${singular} with the given ID, making it publicly visible. Sets the published flag to true."`
+	Unpublish  ResourceUnpublishCmd[K]  `cmd:"" help:"Unpublish the ${singular} with the given ID, hiding it from public view. Sets the published flag to false."`
+	Comments   ResourceCommentsCmd[K]   `cmd:"" help:"List all comments on the ${singular} with the given ID. Comments are internal notes visible to editors."`
+	Comment    ResourceCommentCmd[K]    `cmd:"" help:"Add a comment to the ${singular} with the given ID. Comments are internal notes visible to editors."`
+	Revisions  ResourceRevisionsCmd[K]  `cmd:"" help:"Show the revision history of the ${singular} with the given ID, including who made changes and when."`
+	Translate  ResourceTranslateCmd[K]  `cmd:"" help:"Fill in missing titles and descriptions of ${plural} in other languages using a machine translator. Shows the proposed text before saving and never overwrites existing translations unless --overwrite is given."`
+	Import     ResourceImportCmd[K]     `cmd:"" help:"Create or update ${plural} from a CSV or Excel file. A YAML mapping file maps spreadsheet columns to document fields; rows are validated and matched to existing ${plural} by externalid or trcid. Prints a result per row."`
+	Clone      ResourceCloneCmd[K]      `cmd:"" help:"Create a draft copy of the ${singular} with the given ID. Server-managed fields and the externalid are left out; --set changes fields of the copy and --shift-dates moves its dates, e.g. to next year's edition."`
+	Mark       ResourceMarkCmd[K]       `cmd:"" help:"Add and remove markers on ${plural}: tff <type> mark <id...> +featured -archived, or with --where for all matching ${plural}. Added markers must be in the account's marker dictionary unless --allow-new is given."`
+	Tag        ResourceTagCmd[K]        `cmd:"" help:"Add and remove keywords on ${plural}: tff <type> tag <id...> +music -jazz, or with --where for all matching ${plural}. Added keywords must be in the account's keyword dictionary unless --allow-new is given."`
+	Categorize ResourceCategorizeCmd[K] `cmd:"" help:"List, add and remove the ontology categories of a ${singular}, and set the values of category properties. Added categories must exist for ${plural}, be leaves of the ontology and not be deprecated; they can be given by ID or label."`
+	Media      ResourceMediaCmd[K]      `cmd:"" help:"Manage the images of a ${singular}: tff <type> media <id> list, add <url>, remove <n>, set-main <n> or reorder <n...>. Added images are checked for format, dimensions and file size."`
+}
+
+// resourceFilters are the list filters shared by all resource types.
+type resourceFilters struct {
+	Search       string `short:"s" help:"Full-text search query. Searches across title, description, and other text fields. Supports special syntax: 'tag:keyword' to search by keyword tag, 'marker:name' to search by marker name."`
+	Markers      string `help:"Comma-separated list of markers to filter by. Prefix with '!' to exclude a marker. Example: '!marker1,marker2' excludes marker1 but requires marker2."`
+	Keywords     string `help:"Comma-separated list of keywords to filter by."`
+	Types        string `help:"Comma-separated category types to filter by."`
+	Categories   string `help:"Comma-separated categories to filter by."`
+	WFStatus     string `short:"w" enum:"draft,readyforvalidation,approved,rejected,deleted,archived," default:"" help:"Filter by workflow status. Allowed values: draft, readyforvalidation, approved, rejected, deleted, archived."`
+	Published    string `help:"Filter by published state. Use 'true' for published ${plural}, 'false' for unpublished."`
+	Deleted      bool   `help:"Include deleted ${plural} in results. Default: false."`
+	Owner        string `help:"Filter by owner (username or email)."`
+	UserOrg      string `name:"userorganisation" help:"Filter by user organisation. Use the account name without spaces."`
+	TRCID        string `name:"trcid" help:"Filter by TRC ID (Toeristische Recreatieve Content identifier)."`
+	ExternalID   string `name:"externalid" help:"Filter by external ID."`
+	Language     string `name:"lang" help:"Filter by language. Supported: nl, en, de."`
+	UpdatedSince string `name:"updated-since" help:"Show ${plural} updated after this date. Supports relative time: 2w (2 weeks ago), 3d (3 days ago), 1mo (1 month ago), 1y (1 year ago), named ranges (today, this-week) and absolute dates or timestamps: 2026-01-15, 2026-01-15T09:00."`
+}
+
+func (f *resourceFilters) listOptions() (feedfactory.ListOptions, error) {
+	opts := feedfactory.ListOptions{
+		Search:     f.Search,
+		Markers:    f.Markers,
+		Keywords:   f.Keywords,
+		Types:      f.Types,
+		Categories: f.Categories,
+		WFStatus:   f.WFStatus,
+		Published:  f.Published,
+		Deleted:    f.Deleted,
+		Owner:      f.Owner,
+		UserOrg:    f.UserOrg,
+		TRCID:      f.TRCID,
+		ExternalID: f.ExternalID,
+		Language:   f.Language,
+	}
+	if f.UpdatedSince != "" {
+		iso, err := ParseRelativeISO(f.UpdatedSince)
+		if err != nil {
+			return opts, fmt.Errorf("--updated-since: %w", err)
+		}
+		opts.UpdatedSince = iso
+	}
+	return opts, nil
+}
+
+type ResourceListCmd[K resourceKind] struct {
+	resourceFilters
+	Sort   string   `short:"o" default:"modified" enum:"modified,created,title,wfstatus" help:"Sort results by field. Options: modified (default), created, title, wfstatus."`
+	Asc    bool     `help:"Sort in ascending order. Default is descending (newest first)."`
+	Size   int      `short:"l" default:"25" help:"Number of results per page. Default: 25, maximum: 5000."`
+	Page   int      `short:"p" default:"0" help:"Page number (0-indexed). Default: 0."`
+	JSON   bool     `short:"j" help:"Output full API response as JSON instead of a table."`
+	Fields []string `help:"Only fetch these fields, e.g. id,wfstatus,trcItemDetails.title. Dotted paths select nested fields and a parent field keeps all of its children; id is always included. The list is sent to the API and also applied to the response, so the output is trimmed even when the API returns full documents; only then does it reduce transfer time. Table columns of fields left out stay empty."`
+	Kind   K        `embed:""`
+}
+
+func (c *ResourceListCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	opts, err := c.listOptions()
+	if err != nil {
+		return err
+	}
+	opts.Sort = c.Sort
+	opts.Asc = c.Asc
+	opts.Size = c.Size
+	opts.Page = c.Page
+	opts.Fields = c.Fields
+
+	params, err := c.Kind.params()
+	if err != nil {
+		return err
+	}
+
+	result, err := client.ListResources(ctx, d.Endpoint, opts, params)
+	if err != nil {
+		return err
+	}
+
+	return renderResourceList(result, d.Columns, d.Plural, c.JSON)
+}
+
+type ResourceExportCmd[K resourceKind] struct {
+	File        string `name:"file" short:"o" required:"" help:"Output file path (e.g. export.xlsx). With --chunked the extension picks the format: .xlsx, .csv or .ndjson. An existing file is only replaced once the export is complete and keeps its permissions. Interrupted downloads are not resumed: run the export again."`
+	Format      string `enum:"excel,uitkrant," default:"excel" help:"Export format. 'excel' for Excel spreadsheet (.xlsx), 'uitkrant' for plain text publication format (events only; requires --when, or --date-from and --date-to)."`
+	PropertyIDs string `name:"export-propertyids" help:"Comma-separated list of category property IDs to include as additional columns in the Excel export. Each ID maps to a category property whose value is added as an extra column. Use 'tff dictionary categories' to find IDs."`
+	resourceFilters
+	Sort string `enum:"modified,created,title,wfstatus," default:"" help:"Sort field."`
+	Asc  bool   `help:"Sort ascending."`
+	chunkFlags
+	Kind K `embed:""`
+}
+
+func (c *ResourceExportCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if c.Format != "" && c.Format != "excel" && !contains(d.ExportFormats, c.Format) {
+		return fmt.Errorf("format '%s' is not available for %s", c.Format, d.Plural)
+	}
+	if checker, ok := any(c.Kind).(exportChecker); ok {
+		if err := checker.checkExport(c.Format); err != nil {
+			return err
+		}
+	}
+
+	opts, err := c.listOptions()
+	if err != nil {
+		return err
+	}
+	opts.Sort = c.Sort
+	opts.Asc = c.Asc
+
+	params, err := c.Kind.params()
+	if err != nil {
+		return err
+	}
+
+	if c.Chunked {
+		if c.Format != "" && c.Format != "excel" {
+			return fmt.Errorf("--chunked does not support format '%s'", c.Format)
+		}
+		if c.PropertyIDs != "" {
+			return fmt.Errorf("--export-propertyids is not supported with --chunked")
+		}
+		return c.export(ctx, client, d, c.File, opts, params)
+	}
+
+	exportOpts := feedfactory.ExportOptions{
+		PropertyIDs: c.PropertyIDs,
+		Format:      c.Format,
+	}
+
+	exp, err := client.OpenExport(ctx, d.Endpoint, opts, params, exportOpts)
+	if err != nil {
+		return err
+	}
+	defer exp.Close()
+
+	n, err := writeFileAtomic(c.File, exp, exp.ContentLength)
+	if err != nil {
+		if ctx.Err() != nil {
+			return stopped(ctx, "after downloading %s; %s was not written", formatBytes(n), c.File)
+		}
+		return err
+	}
+
+	fmt.Printf("Exported %s to %s (%d bytes)\n", d.Plural, c.File, n)
+	return nil
+}
+
+type ResourceGetCmd[K resourceKind] struct {
+	ID     string   `arg:"" help:"ID of the ${singular} (required)."`
+	JSON   bool     `short:"j" help:"Output full JSON response instead of formatted text."`
+	Fields []string `help:"Only fetch these fields, e.g. id,wfstatus,calendar. Dotted paths select nested fields and a parent field keeps all of its children; id is always included. The list is sent to the API and also applied to the response."`
+	Groups bool     `help:"Events only: also show the event groups the event belongs to, as eventgroups in JSON output. Reads the ID, title and events of all event groups."`
+}
+
+func (c *ResourceGetCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if c.Groups && d.Endpoint != "events" {
+		return fmt.Errorf("--groups is only available for events")
+	}
+	body, err := client.GetResource(ctx, d.Endpoint, c.ID, c.Fields...)
+	if err != nil {
+		return err
+	}
+	if !c.Groups {
+		return renderResource(body, d.Columns, d.Label, c.JSON)
+	}
+
+	groups, err := eventGroupsOf(ctx, client, c.ID)
+	if err != nil {
+		return err
+	}
+	if body, err = withField(body, "eventgroups", groups); err != nil {
+		return err
+	}
+	if err := renderResource(body, d.Columns, d.Label, c.JSON); err != nil {
+		return err
+	}
+	if format := outputFormat(c.JSON); format == "table" || format == "wide" {
+		printGroups(groups)
+	}
+	return nil
+}
+
+type ResourceDeleteCmd[K resourceKind] struct {
+	ID    string `arg:"" help:"ID of the ${singular} to delete."`
+	Force bool   `short:"f" help:"Skip confirmation prompt."`
+}
+
+func (c *ResourceDeleteCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if !c.Force {
+		fmt.Printf("Are you sure you want to delete %s %s? [y/N] ", d.Singular, c.ID)
+		var confirm string
+		fmt.Scanln(&confirm)
+		if strings.ToLower(confirm) != "y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := client.DeleteResource(ctx, d.Endpoint, c.ID); err != nil {
+		return fmt.Errorf("deleting %s: %w", d.Singular, err)
+	}
+	fmt.Printf("%s %s deleted.\n", capitalize(d.Singular), c.ID)
+	return nil
+}
+
+type ResourcePublishCmd[K resourceKind] struct {
+	ID string `arg:"" help:"ID of the ${singular} to publish."`
+}
+
+func (c *ResourcePublishCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if err := client.PublishResource(ctx, d.Endpoint, c.ID); err != nil {
+		return fmt.Errorf("publishing %s: %w", d.Singular, err)
+	}
+	fmt.Printf("%s %s published.\n", capitalize(d.Singular), c.ID)
+	return nil
+}
+
+type ResourceUnpublishCmd[K resourceKind] struct {
+	ID string `arg:"" help:"ID of the ${singular} to unpublish."`
+}
+
+func (c *ResourceUnpublishCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if err := client.UnpublishResource(ctx, d.Endpoint, c.ID); err != nil {
+		return fmt.Errorf("unpublishing %s: %w", d.Singular, err)
+	}
+	fmt.Printf("%s %s unpublished.\n", capitalize(d.Singular), c.ID)
+	return nil
+}
+
+type ResourceCommentsCmd[K resourceKind] struct {
+	ID   string `arg:"" help:"ID of the ${singular} to list comments for."`
+	JSON bool   `short:"j" help:"Output as JSON."`
+}
+
+func (c *ResourceCommentsCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	body, err := client.GetComments(ctx, describe[K]().Endpoint, c.ID)
+	if err != nil {
+		return err
+	}
+
+	return renderComments(body, c.JSON)
+}
+
+type ResourceCommentCmd[K resourceKind] struct {
+	ID      string `arg:"" help:"ID of the ${singular} to comment on."`
+	Message string `arg:"" help:"Comment message text."`
+}
+
+func (c *ResourceCommentCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	if err := client.AddComment(ctx, d.Endpoint, c.ID, c.Message); err != nil {
+		return fmt.Errorf("adding comment: %w", err)
+	}
+	fmt.Printf("Comment added to %s %s.\n", d.Singular, c.ID)
+	return nil
+}
+
+type ResourceRevisionsCmd[K resourceKind] struct {
+	ID   string `arg:"" help:"ID of the ${singular} to show revisions for."`
+	JSON bool   `short:"j" help:"Output as JSON."`
+}
+
+func (c *ResourceRevisionsCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	body, err := client.GetRevisions(ctx, describe[K]().Endpoint, c.ID)
+	if err != nil {
+		return err
+	}
+
+	return renderRevisions(body, c.JSON)
+}
+
+type ResourceTranslateCmd[K resourceKind] struct {
+	TranslateFlags
+}
+
+func (c *ResourceTranslateCmd[K]) Run(ctx context.Context, client *feedfactory.Client) error {
+	d := describe[K]()
+	return c.run(ctx, client, d.Endpoint, d.Singular)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// resourceRow is a resource in a list together with the document it was parsed
+// from, which is what structured output formats print.
+type resourceRow struct {
+	feedfactory.Resource
+	raw json.RawMessage
+}
+
+// resourceColumns returns the columns for a resource list: ID and title, the
+// type-specific columns, status, and the wide columns shared by all types.
+func resourceColumns(titleWidth int, extra ...column[resourceRow]) []column[resourceRow] {
+	cols := []column[resourceRow]{
+		{Name: "id", Value: func(r resourceRow) string { return r.ID }},
+		{Name: "title", Width: titleWidth, Value: func(r resourceRow) string { return r.GetTitle() }},
+	}
+	cols = append(cols, extra...)
+	return append(cols,
+		column[resourceRow]{Name: "status", Value: func(r resourceRow) string { return r.WFStatus }},
+		column[resourceRow]{Name: "published", Value: func(r resourceRow) string { return boolYesNo(r.Published) }},
+		column[resourceRow]{Name: "owner", Wide: true, Value: func(r resourceRow) string { return r.Owner }},
+		column[resourceRow]{Name: "organisation", Wide: true, Value: func(r resourceRow) string { return r.UserOrg }},
+		column[resourceRow]{Name: "markers", Wide: true, Width: 30, Value: func(r resourceRow) string { return strings.Join(r.GetMarkers(), ",") }},
+		column[resourceRow]{Name: "trcid", Wide: true, Value: func(r resourceRow) string { return r.TRCID }},
+		column[resourceRow]{Name: "externalid", Wide: true, Value: func(r resourceRow) string { return r.ExternalID }},
+		column[resourceRow]{Name: "created", Wide: true, Value: func(r resourceRow) string { return formatTimestamp(r.Created) }},
+		column[resourceRow]{Name: "updated", Wide: true, Value: func(r resourceRow) string { return formatTimestamp(r.LastUpdated) }},
+	)
+}
+
+// cityColumn is the CITY column of events, locations and venues.
+var cityColumn = column[resourceRow]{Name: "city", Width: 20, Value: func(r resourceRow) string { return r.GetCity() }}
+
+// renderResourceList renders one page of a list command.
+func renderResourceList(result *feedfactory.SearchResult, cols []column[resourceRow], plural string, jsonFlag bool) error {
+	resources, err := feedfactory.ParseResources(result.Results)
+	if err != nil {
+		return err
+	}
+	rows := make([]resourceRow, len(resources))
+	for i, r := range resources {
+		rows[i] = resourceRow{Resource: r, raw: result.Results[i]}
+	}
+
+	return view[resourceRow]{
+		Columns:  cols,
+		Rows:     rows,
+		Doc:      func(r resourceRow) interface{} { return rawDoc(r.raw) },
+		Document: rawDoc(mustMarshal(result)),
+		Empty:    fmt.Sprintf("No %s found.", plural),
+		Footer:   fmt.Sprintf("Showing %d of %d %s (page %d)", len(rows), result.Hits, plural, result.Page),
+	}.render(jsonFlag)
+}
+
+// renderResource renders a single resource from a get command. Table output is
+// the detail view; csv and ids use the list columns.
+func renderResource(body []byte, cols []column[resourceRow], label string, jsonFlag bool) error {
+	var r feedfactory.Resource
+	if err := json.Unmarshal(body, &r); err != nil {
+		if outputFormat(jsonFlag) == "json" {
+			return printRawJSON(body)
+		}
+		return fmt.Errorf("parsing %s: %w", strings.ToLower(label), err)
+	}
+
+	row := resourceRow{Resource: r, raw: body}
+	return view[resourceRow]{
+		Columns:  cols,
+		Rows:     []resourceRow{row},
+		Doc:      func(r resourceRow) interface{} { return rawDoc(r.raw) },
+		Document: rawDoc(body),
+		Text: func() error {
+			printResourceDetail(r, label)
+			return nil
+		},
+	}.render(jsonFlag)
+}
+
+func printResourceDetail(r feedfactory.Resource, resourceType string) {
+	fmt.Printf("%s: %s\n", resourceType, r.GetTitle())
+	fmt.Printf("ID: %s\n", r.ID)
+	if r.Slug != "" {
+		fmt.Printf("Slug: %s\n", r.Slug)
+	}
+	if r.TRCID != "" {
+		fmt.Printf("TRC ID: %s\n", r.TRCID)
+	}
+	if r.ExternalID != "" {
+		fmt.Printf("External ID: %s\n", r.ExternalID)
+	}
+	fmt.Printf("Status: %s\n", r.WFStatus)
+	fmt.Printf("Published: %s\n", boolYesNo(r.Published))
+	if r.Deleted {
+		fmt.Printf("Deleted: Yes\n")
+	}
+	if r.Owner != "" {
+		fmt.Printf("Owner: %s\n", r.Owner)
+	}
+	if r.UserOrg != "" {
+		fmt.Printf("Organisation: %s\n", r.UserOrg)
+	}
+	if r.EntityType != "" {
+		fmt.Printf("Type: %s\n", r.EntityType)
+	}
+
+	// Titles and descriptions in all languages
+	if len(r.TRCItemDetails) > 0 {
+		if len(r.TRCItemDetails) > 1 {
+			fmt.Println("\nTitles:")
+			for _, d := range r.TRCItemDetails {
+				fmt.Printf("  %s: %s\n", d.Lang, d.Title)
+			}
+		}
+
+		fmt.Println("\nShort Description:")
+		for _, d := range r.TRCItemDetails {
+			if d.ShortDescription != "" {
+				fmt.Printf("  %s: %s\n", d.Lang, truncate(d.ShortDescription, 200))
+			}
+		}
+	}
+
+	// Location
+	if r.Location != nil && r.Location.Address != nil {
+		a := r.Location.Address
+		fmt.Println("\nLocation:")
+		if a.Street != "" {
+			line := a.Street
+			if a.HouseNr != "" {
+				line += " " + a.HouseNr
+			}
+			fmt.Printf("  Address: %s\n", line)
+		}
+		if a.ZipCode != "" || a.City != "" {
+			fmt.Printf("  City: %s %s\n", a.ZipCode, a.City)
+		}
+		if a.Latitude != 0 || a.Longitude != 0 {
+			fmt.Printf("  Coordinates: %.6f, %.6f\n", a.Latitude, a.Longitude)
+		}
+	}
+
+	// Calendar (events)
+	if r.Calendar != nil && len(r.Calendar.SingleDates) > 0 {
+		fmt.Println("\nDates:")
+		limit := len(r.Calendar.SingleDates)
+		if limit > 10 {
+			limit = 10
+		}
+		for _, d := range r.Calendar.SingleDates[:limit] {
+			line := d.Date
+			if d.StartTime != "" {
+				line += " " + d.StartTime
+			}
+			if d.EndTime != "" {
+				line += " - " + d.EndTime
+			}
+			fmt.Printf("  %s\n", line)
+		}
+		if len(r.Calendar.SingleDates) > 10 {
+			fmt.Printf("  ... and %d more dates\n", len(r.Calendar.SingleDates)-10)
+		}
+	}
+
+	// Physical (routes)
+	if r.Physical != nil {
+		if r.Physical.RouteType != "" {
+			fmt.Printf("\nRoute Type: %s\n", r.Physical.RouteType)
+		}
+		if r.Physical.Distance != "" {
+			fmt.Printf("Distance: %s\n", r.Physical.Distance)
+		}
+		if r.Physical.Duration != "" {
+			fmt.Printf("Duration: %s\n", r.Physical.Duration)
+		}
+	}
+
+	// Contact
+	if r.ContactInfo != nil {
+		phone := r.ContactInfo.GetPhone()
+		email := r.ContactInfo.GetEmail()
+		if phone != "" || email != "" {
+			fmt.Println("\nContact:")
+			if phone != "" {
+				fmt.Printf("  Phone: %s\n", phone)
+			}
+			if email != "" {
+				fmt.Printf("  Email: %s\n", email)
+			}
+		}
+		if len(r.ContactInfo.URLs) > 0 {
+			fmt.Println("\nContact URLs:")
+			for _, u := range r.ContactInfo.URLs {
+				label := u.URLServiceType
+				if label == "" {
+					label = "url"
+				}
+				fmt.Printf("  %s: %s\n", label, u.URL)
+			}
+		}
+	}
+
+	// URLs
+	if len(r.URLs) > 0 {
+		fmt.Println("\nURLs:")
+		for _, u := range r.URLs {
+			label := u.URLType
+			if u.Label != "" {
+				label = u.Label
+			}
+			fmt.Printf("  %s: %s\n", label, u.URL)
+		}
+	}
+
+	// Media
+	if len(r.Media) > 0 {
+		fmt.Println("\nMedia:")
+		for _, m := range r.Media {
+			main := ""
+			if m.Main {
+				main = " (main)"
+			}
+			fmt.Printf("  %s%s: %s\n", m.MediaType, main, m.URL)
+		}
+	}
+
+	// Types / Categories
+	if len(r.Types) > 0 {
+		fmt.Printf("\nTypes: %s\n", strings.Join(r.Types, ", "))
+	}
+
+	// Keywords
+	keywords := r.GetKeywords()
+	if len(keywords) > 0 {
+		fmt.Println("\nKeywords:")
+		for _, k := range keywords {
+			label := k.Label
+			if label == "" {
+				label = k.Value
+			}
+			fmt.Printf("  %s\n", label)
+		}
+	}
+
+	// Markers
+	markers := r.GetMarkers()
+	if len(markers) > 0 {
+		fmt.Printf("\nMarkers: %s\n", strings.Join(markers, ", "))
+	}
+
+	// Dates
+	fmt.Println()
+	if created := formatTimestamp(r.Created); created != "" {
+		fmt.Printf("Created: %s\n", created)
+	}
+	if updated := formatTimestamp(r.LastUpdated); updated != "" {
+		fmt.Printf("Last Updated: %s\n", updated)
+	}
+}
+
+// rawList decodes a JSON array, or an object wrapping one under key, into its
+// raw entries.
+func rawList(body []byte, key string) ([]json.RawMessage, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(body, &list); err == nil {
+		return list, nil
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	if inner, ok := wrapper[key]; ok {
+		if err := json.Unmarshal(inner, &list); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+type commentRow struct {
+	feedfactory.Comment
+	raw json.RawMessage
+}
+
+func renderComments(body []byte, jsonFlag bool) error {
+	list, err := rawList(body, "comments")
+	if err != nil {
+		// Might be empty or unexpected format, just print raw
+		return printRawJSON(body)
+	}
+	rows := make([]commentRow, 0, len(list))
+	for _, raw := range list {
+		var c feedfactory.Comment
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return printRawJSON(body)
+		}
+		rows = append(rows, commentRow{Comment: c, raw: raw})
+	}
+
+	return view[commentRow]{
+		Columns: []column[commentRow]{
+			{Name: "id", Wide: true, Value: func(c commentRow) string { return c.ID }},
+			{Name: "created", Value: func(c commentRow) string { return formatTimestamp(c.Created) }},
+			{Name: "author", Value: func(c commentRow) string { return c.Author }},
+			{Name: "text", Width: 80, Value: func(c commentRow) string { return c.Text }},
+		},
+		Rows:     rows,
+		Doc:      func(c commentRow) interface{} { return rawDoc(c.raw) },
+		Document: rawDoc(body),
+		Text: func() error {
+			if len(rows) == 0 {
+				fmt.Println("No comments.")
+				return nil
+			}
+			for _, c := range rows {
+				fmt.Printf("[%s] %s:\n  %s\n\n", formatTimestamp(c.Created), c.Author, c.Text)
+			}
+			return nil
+		},
+	}.render(jsonFlag)
+}
+
+type revisionRow struct {
+	feedfactory.Revision
+	raw json.RawMessage
+}
+
+func renderRevisions(body []byte, jsonFlag bool) error {
+	list, err := rawList(body, "revisions")
+	if err != nil {
+		return printRawJSON(body)
+	}
+	rows := make([]revisionRow, 0, len(list))
+	for _, raw := range list {
+		var r feedfactory.Revision
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return printRawJSON(body)
+		}
+		rows = append(rows, revisionRow{Revision: r, raw: raw})
+	}
+
+	return view[revisionRow]{
+		Columns: []column[revisionRow]{
+			{Name: "id", Wide: true, Value: func(r revisionRow) string { return r.ID }},
+			{Name: "created", Value: func(r revisionRow) string { return formatTimestamp(r.Created) }},
+			{Name: "author", Value: func(r revisionRow) string { return r.Author }},
+			{Name: "comment", Width: 80, Value: func(r revisionRow) string { return r.Comment }},
+		},
+		Rows:     rows,
+		Doc:      func(r revisionRow) interface{} { return rawDoc(r.raw) },
+		Document: rawDoc(body),
+		Text: func() error {
+			if len(rows) == 0 {
+				fmt.Println("No revisions.")
+				return nil
+			}
+			for _, r := range rows {
+				comment := ""
+				if r.Comment != "" {
+					comment = " — " + r.Comment
+				}
+				fmt.Printf("[%s] %s%s\n", formatTimestamp(r.Created), r.Author, comment)
+			}
+			return nil
+		},
+	}.render(jsonFlag)
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// resourceTypes lists the API endpoints of all resource types, in display order.
+var resourceTypes = func() []string {
+	types := make([]string, len(resourceKinds))
+	for i, d := range resourceKinds {
+		types[i] = d.Endpoint
+	}
+	return types
+}()
+
+// scanPageSize is the page size used when paging through all resources of a type.
+const scanPageSize = 500
+
+// ScanFlags selects the set of resources a scanning command (report, lint, ...) works on.
+// Resources are paged from the API, or read from a file saved earlier with 'list -j'.
+type ScanFlags struct {
+	Input        string `short:"i" type:"existingfile" help:"Read resources from a local file instead of paging through the API. Accepts the output of 'list -j', a JSON array of resources, or newline-delimited JSON (one resource per line). The filters below are applied to the file, except --search, which needs the API."`
+	Search       string `short:"s" help:"Full-text search query. Supports 'tag:keyword' and 'marker:name' syntax."`
+	Markers      string `help:"Comma-separated markers filter. Prefix with '!' to exclude."`
+	Keywords     string `help:"Comma-separated keywords filter."`
+	WFStatus     string `short:"w" enum:"draft,readyforvalidation,approved,rejected,deleted,archived," default:"" help:"Filter by workflow status."`
+	Published    string `help:"Filter by published state (true/false)."`
+	Owner        string `help:"Filter by owner."`
+	UserOrg      string `name:"userorganisation" help:"Filter by user organisation."`
+	UpdatedSince string `name:"updated-since" help:"Items updated after date. Relative: 2w, 3d, 1mo, 1y. Absolute: 2026-01-15."`
+	Limit        int    `default:"0" help:"Stop after this many resources per type. Default: 0 (no limit)."`
+}
+
+func (f *ScanFlags) listOptions() (feedfactory.ListOptions, error) {
+	opts := feedfactory.ListOptions{
+		Search:    f.Search,
+		Markers:   f.Markers,
+		Keywords:  f.Keywords,
+		WFStatus:  f.WFStatus,
+		Published: f.Published,
+		Owner:     f.Owner,
+		UserOrg:   f.UserOrg,
+	}
+	if f.UpdatedSince != "" {
+		iso, err := ParseRelativeISO(f.UpdatedSince)
+		if err != nil {
+			return opts, fmt.Errorf("--updated-since: %w", err)
+		}
+		opts.UpdatedSince = iso
+	}
+	return opts, nil
+}
+
+// scanTypes normalizes the resource type arguments of a scanning command.
+// No arguments means all resource types. With --input only a single type is
+// allowed, since the file does not say which type its resources are.
+func (f *ScanFlags) scanTypes(args []string) ([]string, error) {
+	types := resourceTypes
+	if len(args) > 0 {
+		types = make([]string, 0, len(args))
+		for _, a := range args {
+			endpoint := feedfactory.ResourceTypeToEndpoint(a)
+			if !isResourceType(endpoint) {
+				return nil, fmt.Errorf("unknown resource type %q (use one of: %s)", a, strings.Join(resourceTypes, ", "))
+			}
+			types = append(types, endpoint)
+		}
+	}
+	if f.Input != "" && len(types) != 1 {
+		return nil, fmt.Errorf("--input requires exactly one resource type")
+	}
+	return types, nil
+}
+
+// scan returns all resources of the given type matching the flags.
+func (f *ScanFlags) scan(ctx context.Context, client *feedfactory.Client, resourceType string) ([]feedfactory.Resource, error) {
+	if f.Input != "" {
+		if f.Search != "" {
+			return nil, fmt.Errorf("--search needs the API and cannot be combined with --input")
+		}
+		raw, err := readResourcesFile(f.Input)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := feedfactory.ParseResources(raw)
+		if err != nil {
+			return nil, err
+		}
+		resources, err = f.filter(resources)
+		if err != nil {
+			return nil, err
+		}
+		if f.Limit > 0 && len(resources) > f.Limit {
+			resources = resources[:f.Limit]
+		}
+		return resources, nil
+	}
+
+	opts, err := f.listOptions()
+	if err != nil {
+		return nil, err
+	}
+	return fetchAllResources(ctx, client, resourceType, opts, f.Limit)
+}
+
+// filter applies the list filters to resources read with --input, as the API
+// would have. Markers prefixed with '!' must be absent, all others present;
+// keywords must all be present.
+func (f *ScanFlags) filter(resources []feedfactory.Resource) ([]feedfactory.Resource, error) {
+	var since time.Time
+	if f.UpdatedSince != "" {
+		iso, err := ParseRelativeISO(f.UpdatedSince)
+		if err != nil {
+			return nil, fmt.Errorf("--updated-since: %w", err)
+		}
+		if since, err = time.Parse(time.RFC3339, iso); err != nil {
+			return nil, err
+		}
+	}
+	if f.Published != "" && f.Published != "true" && f.Published != "false" {
+		return nil, fmt.Errorf("--published must be true or false")
+	}
+
+	var out []feedfactory.Resource
+	for _, r := range resources {
+		if f.WFStatus != "" && r.WFStatus != f.WFStatus ||
+			f.Published != "" && strconv.FormatBool(r.Published) != f.Published ||
+			f.Owner != "" && !strings.EqualFold(r.Owner, f.Owner) ||
+			f.UserOrg != "" && !strings.EqualFold(r.UserOrg, f.UserOrg) ||
+			!since.IsZero() && !r.LastUpdated.After(since) ||
+			!matchesMarkers(r.GetMarkers(), f.Markers) ||
+			!matchesKeywords(r.GetKeywords(), f.Keywords) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func matchesMarkers(markers []string, filter string) bool {
+	for _, m := range splitList(filter) {
+		if name, ok := strings.CutPrefix(m, "!"); ok {
+			if containsFold(markers, name) {
+				return false
+			}
+		} else if !containsFold(markers, m) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesKeywords(keywords []feedfactory.Keyword, filter string) bool {
+	for _, want := range splitList(filter) {
+		found := false
+		for _, k := range keywords {
+			if strings.EqualFold(k.Label, want) || strings.EqualFold(k.Value, want) || k.ID == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchAllResources pages through a list endpoint until all hits (or limit, if > 0)
+// have been collected. Progress is reported on stderr so stdout stays clean.
+func fetchAllResources(ctx context.Context, client *feedfactory.Client, resourceType string, opts feedfactory.ListOptions, limit int) ([]feedfactory.Resource, error) {
+	opts.Size = scanPageSize
+	if limit > 0 && limit < scanPageSize {
+		opts.Size = limit
+	}
+
+	var resources []feedfactory.Resource
+	hits := 0
+	for page := 0; ; page++ {
+		opts.Page = page
+		result, err := client.ListResources(ctx, resourceType, opts, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprintln(os.Stderr)
+				return nil, stopped(ctx, "after fetching %d of %d %s", len(resources), hits, resourceType)
+			}
+			return nil, err
+		}
+		hits = result.Hits
+		parsed, err := feedfactory.ParseResources(result.Results)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, parsed...)
+		fmt.Fprintf(os.Stderr, "Fetched %d of %d %s\r", len(resources), result.Hits, resourceType)
+
+		if len(parsed) == 0 || len(resources) >= result.Hits || (limit > 0 && len(resources) >= limit) {
+			break
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if limit > 0 && len(resources) > limit {
+		resources = resources[:limit]
+	}
+	return resources, nil
+}
+
+// readResourcesFile reads raw resources from a saved list response, a JSON array
+// or newline-delimited JSON.
+func readResourcesFile(path string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return raw, nil
+	}
+
+	var result feedfactory.SearchResult
+	if err := json.Unmarshal(data, &result); err == nil && result.Results != nil {
+		return result.Results, nil
+	}
+
+	var raw []json.RawMessage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if !json.Valid(text) {
+			return nil, fmt.Errorf("parsing %s: line %d is not valid JSON", path, line)
+		}
+		raw = append(raw, json.RawMessage(append([]byte(nil), text...)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+func isResourceType(endpoint string) bool {
+	for _, t := range resourceTypes {
+		if t == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -4,52 +4,311 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
 )
 
-var relativeTimeRe = regexp.MustCompile(`^(\d+)(d|w|mo|y)$`)
+// apiLocation is the time zone FeedFactory uses for event dates and times (Dutch
+// local time). Date expressions are evaluated in this zone so results don't shift
+// by a day on machines (e.g. CI runners) that run in UTC.
+var apiLocation = feedfactory.APILocation
+
+// displayLocation is the time zone timestamps are shown in. It defaults to the API
+// zone and is set from --tz or FF_TIMEZONE.
+var displayLocation = feedfactory.APILocation
+
+// SetDisplayTimeZone sets the zone used to show timestamps: an IANA name such as
+// Europe/Amsterdam or UTC, or "Local" for the machine's zone. An empty name keeps
+// the default.
+func SetDisplayTimeZone(name string) error {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	displayLocation = loc
+	return nil
+}
+
+// formatTimestamp formats an API timestamp in the display time zone.
+func formatTimestamp(t feedfactory.Timestamp) string {
+	return t.Display(displayLocation)
+}
+
+const dateLayout = "2006-01-02"
+
+// clock returns the time relative date expressions are resolved against. Tests
+// replace it.
+var clock = time.Now
+
+var (
+	offsetRe  = regexp.MustCompile(`^([+-]?)(\d+)(d|w|mo|y)$`)
+	isoWeekRe = regexp.MustCompile(`^(\d{4})-?W(\d{1,2})$`)
+	monthRe   = regexp.MustCompile(`^\d{4}-\d{2}$`)
+)
+
+// DateRange is the half-open interval [From, To). Offsets and timestamps resolve
+// to a single point, where From equals To.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// FirstDay returns the first date in the range as yyyy-mm-dd, in Dutch local time.
+func (r DateRange) FirstDay() string {
+	return r.From.In(apiLocation).Format(dateLayout)
+}
+
+// LastDay returns the last date included in the range as yyyy-mm-dd, in Dutch local time.
+func (r DateRange) LastDay() string {
+	if r.To.After(r.From) {
+		return r.To.Add(-time.Nanosecond).In(apiLocation).Format(dateLayout)
+	}
+	return r.From.In(apiLocation).Format(dateLayout)
+}
+
+// ParseDateExpr parses a date expression relative to now. Supported forms:
+//
+//	+2w, -3d, +1mo, 1y       offsets in days, weeks, months or years; an offset
+//	                         without a sign points into the past when past is
+//	                         true, otherwise into the future
+//	today, tomorrow, yesterday
+//	this-week, next-week, last-week           weeks start on Monday
+//	this-weekend, next-weekend                Saturday and Sunday
+//	this-month, next-month, last-month
+//	2026-W12                 ISO week
+//	2026-03                  calendar month
+//	2026-03-15               date
+//	2026-03-15T20:00:00+01:00, 2026-03-15T20:00, 2026-03-15 20:00
+//	                         timestamps; without an offset they are Dutch local time
+//
+// Calendar expressions are evaluated in the Europe/Amsterdam time zone.
+func ParseDateExpr(s string, now time.Time, past bool) (DateRange, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	now = now.In(apiLocation)
+	today := startOfDay(now)
 
-// ParseRelativeTime parses relative time expressions like "3d", "2w", "1mo", "1y"
-// or absolute dates like "2026-01-15". For relative expressions, returns the time
-// that many units ago from now.
-func ParseRelativeTime(s string) (time.Time, error) {
-	if m := relativeTimeRe.FindStringSubmatch(s); m != nil {
-		n, _ := strconv.Atoi(m[1])
-		now := time.Now()
-		switch m[2] {
+	if m := offsetRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" || (m[1] == "" && past) {
+			n = -n
+		}
+		var t time.Time
+		switch m[3] {
 		case "d":
-			return now.AddDate(0, 0, -n), nil
+			t = now.AddDate(0, 0, n)
 		case "w":
-			return now.AddDate(0, 0, -n*7), nil
+			t = now.AddDate(0, 0, n*7)
 		case "mo":
-			return now.AddDate(0, -n, 0), nil
+			t = now.AddDate(0, n, 0)
 		case "y":
-			return now.AddDate(-n, 0, 0), nil
+			t = now.AddDate(n, 0, 0)
+		}
+		return DateRange{From: t, To: t}, nil
+	}
+
+	day := func(offset int) DateRange {
+		from := today.AddDate(0, 0, offset)
+		return DateRange{From: from, To: from.AddDate(0, 0, 1)}
+	}
+	week := func(offset int) DateRange {
+		from := startOfWeek(today).AddDate(0, 0, 7*offset)
+		return DateRange{From: from, To: from.AddDate(0, 0, 7)}
+	}
+	weekend := func(offset int) DateRange {
+		from := startOfWeek(today).AddDate(0, 0, 5+7*offset)
+		return DateRange{From: from, To: from.AddDate(0, 0, 2)}
+	}
+	month := func(offset int) DateRange {
+		from := time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, apiLocation)
+		return DateRange{From: from, To: from.AddDate(0, 1, 0)}
+	}
+
+	switch s {
+	case "now":
+		return DateRange{From: now, To: now}, nil
+	case "today":
+		return day(0), nil
+	case "tomorrow":
+		return day(1), nil
+	case "yesterday":
+		return day(-1), nil
+	case "this-week":
+		return week(0), nil
+	case "next-week":
+		return week(1), nil
+	case "last-week":
+		return week(-1), nil
+	case "this-weekend":
+		return weekend(0), nil
+	case "next-weekend":
+		return weekend(1), nil
+	case "this-month":
+		return month(0), nil
+	case "next-month":
+		return month(1), nil
+	case "last-month":
+		return month(-1), nil
+	}
+
+	if m := isoWeekRe.FindStringSubmatch(strings.ToUpper(s)); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		w, _ := strconv.Atoi(m[2])
+		if w < 1 || w > isoWeeksInYear(year) {
+			return DateRange{}, fmt.Errorf("invalid ISO week %q: %d has %d weeks", s, year, isoWeeksInYear(year))
+		}
+		from := isoWeekStart(year).AddDate(0, 0, (w-1)*7)
+		return DateRange{From: from, To: from.AddDate(0, 0, 7)}, nil
+	}
+
+	if monthRe.MatchString(s) {
+		from, err := time.ParseInLocation("2006-01", s, apiLocation)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("invalid month %q", s)
+		}
+		return DateRange{From: from, To: from.AddDate(0, 1, 0)}, nil
+	}
+
+	if t, err := time.ParseInLocation(dateLayout, s, apiLocation); err == nil {
+		return DateRange{From: t, To: t.AddDate(0, 0, 1)}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return DateRange{From: t, To: t}, nil
+	}
+	for _, layout := range []string{"2006-01-02t15:04:05", "2006-01-02t15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, apiLocation); err == nil {
+			return DateRange{From: t, To: t}, nil
 		}
 	}
 
-	t, err := time.Parse("2006-01-02", s)
-	if err == nil {
-		return t, nil
+	return DateRange{}, fmt.Errorf("invalid date expression %q (use e.g. +2w, -3d, today, this-weekend, next-month, 2026-W12, 2026-01-15 or 2026-01-15T20:00)", s)
+}
+
+// ParseRangeExpr parses a --when value: a single expression covering a period
+// (today, next-week, 2026-W12, 2026-03, ...) or two expressions joined by "..",
+// e.g. "today..+2w" or "2026-03-01..2026-03-31". Offsets point into the future.
+func ParseRangeExpr(s string, now time.Time) (DateRange, error) {
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		start, err := ParseDateExpr(from, now, false)
+		if err != nil {
+			return DateRange{}, err
+		}
+		end, err := ParseDateExpr(to, now, false)
+		if err != nil {
+			return DateRange{}, err
+		}
+		r := DateRange{From: start.From, To: end.To}
+		if end.To.Equal(end.From) {
+			// A point as the end: include that whole day.
+			r.To = startOfDay(end.From.In(apiLocation)).AddDate(0, 0, 1)
+		}
+		if r.To.Before(r.From) {
+			return DateRange{}, fmt.Errorf("invalid range %q: end is before start", s)
+		}
+		return r, nil
 	}
 
-	return time.Time{}, fmt.Errorf("invalid time expression %q (use e.g. 3d, 2w, 1mo, 1y, or 2026-01-15)", s)
+	r, err := ParseDateExpr(s, now, false)
+	if err != nil {
+		return DateRange{}, err
+	}
+	if r.To.Equal(r.From) {
+		// A point such as +2w means "from today up to and including that day".
+		from := startOfDay(now.In(apiLocation))
+		to := startOfDay(r.From.In(apiLocation)).AddDate(0, 0, 1)
+		if to.Before(from) {
+			from, to = startOfDay(r.From.In(apiLocation)), from.AddDate(0, 0, 1)
+		}
+		r = DateRange{From: from, To: to}
+	}
+	return r, nil
 }
 
-// ParseRelativeDate parses a relative or absolute date and returns it in yyyy-mm-dd format.
-func ParseRelativeDate(s string) (string, error) {
-	t, err := ParseRelativeTime(s)
+// ParseRelativeISO parses a date expression for "updated since" style filters and
+// returns its start in ISO 8601 format. Offsets without a sign point into the past.
+func ParseRelativeISO(s string) (string, error) {
+	r, err := ParseDateExpr(s, clock(), true)
 	if err != nil {
 		return "", err
 	}
-	return t.Format("2006-01-02"), nil
+	return r.From.In(apiLocation).Format(time.RFC3339), nil
 }
 
-// ParseRelativeISO parses a relative or absolute date and returns it in ISO 8601 format.
-func ParseRelativeISO(s string) (string, error) {
-	t, err := ParseRelativeTime(s)
+// ParseDateFrom parses a date expression for the start of an event date range and
+// returns the first day it covers in yyyy-mm-dd format. Offsets without a sign
+// point into the future.
+func ParseDateFrom(s string) (string, error) {
+	r, err := ParseDateExpr(s, clock(), false)
+	if err != nil {
+		return "", err
+	}
+	return r.FirstDay(), nil
+}
+
+// ParseDateTo parses a date expression for the end of an event date range and
+// returns the last day it covers in yyyy-mm-dd format, so "--date-to next-month"
+// includes the whole of next month. Offsets without a sign point into the future.
+func ParseDateTo(s string) (string, error) {
+	r, err := ParseDateExpr(s, clock(), false)
 	if err != nil {
 		return "", err
 	}
-	return t.Format(time.RFC3339), nil
+	return r.LastDay(), nil
+}
+
+// eventDateRange resolves the --when, --date-from and --date-to flags of event
+// commands into the yyyy-mm-dd bounds sent to the API.
+func eventDateRange(when, dateFrom, dateTo string) (string, string, error) {
+	if when != "" {
+		if dateFrom != "" || dateTo != "" {
+			return "", "", fmt.Errorf("--when cannot be combined with --date-from or --date-to")
+		}
+		r, err := ParseRangeExpr(when, clock())
+		if err != nil {
+			return "", "", fmt.Errorf("--when: %w", err)
+		}
+		return r.FirstDay(), r.LastDay(), nil
+	}
+
+	var from, to string
+	if dateFrom != "" {
+		d, err := ParseDateFrom(dateFrom)
+		if err != nil {
+			return "", "", fmt.Errorf("--date-from: %w", err)
+		}
+		from = d
+	}
+	if dateTo != "" {
+		d, err := ParseDateTo(dateTo)
+		if err != nil {
+			return "", "", fmt.Errorf("--date-to: %w", err)
+		}
+		to = d
+	}
+	return from, to, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+// isoWeekStart returns the Monday of ISO week 1 of year: the week containing 4 January.
+func isoWeekStart(year int) time.Time {
+	return startOfWeek(time.Date(year, time.January, 4, 0, 0, 0, 0, apiLocation))
+}
+
+func isoWeeksInYear(year int) int {
+	_, w := time.Date(year, time.December, 28, 0, 0, 0, 0, apiLocation).ISOWeek()
+	return w
 }
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// at returns a time in Dutch local time.
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, apiLocation)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// fixClock makes the package clock return t for the rest of the test.
+func fixClock(t *testing.T, now time.Time) {
+	t.Helper()
+	saved := clock
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = saved })
+}
+
+func TestParseDateExprPeriods(t *testing.T) {
+	tests := []struct {
+		now, expr     string
+		first, last   string
+		wantErrSubstr string
+	}{
+		// Wednesday 25 March 2026, the week that ends with the switch to summer time.
+		{now: "2026-03-25 10:00", expr: "today", first: "2026-03-25", last: "2026-03-25"},
+		{now: "2026-03-25 10:00", expr: "Tomorrow", first: "2026-03-26", last: "2026-03-26"},
+		{now: "2026-03-25 10:00", expr: "yesterday", first: "2026-03-24", last: "2026-03-24"},
+		{now: "2026-03-25 10:00", expr: "this-week", first: "2026-03-23", last: "2026-03-29"},
+		{now: "2026-03-25 10:00", expr: "next-week", first: "2026-03-30", last: "2026-04-05"},
+		{now: "2026-03-25 10:00", expr: "last-week", first: "2026-03-16", last: "2026-03-22"},
+		{now: "2026-03-25 10:00", expr: "this-weekend", first: "2026-03-28", last: "2026-03-29"},
+		{now: "2026-03-25 10:00", expr: "next-weekend", first: "2026-04-04", last: "2026-04-05"},
+		{now: "2026-03-25 10:00", expr: "this-month", first: "2026-03-01", last: "2026-03-31"},
+		{now: "2026-03-25 10:00", expr: "next-month", first: "2026-04-01", last: "2026-04-30"},
+		{now: "2026-03-25 10:00", expr: "last-month", first: "2026-02-01", last: "2026-02-28"},
+		{now: "2026-03-25 10:00", expr: "2026-03", first: "2026-03-01", last: "2026-03-31"},
+		{now: "2026-03-25 10:00", expr: "2026-03-15", first: "2026-03-15", last: "2026-03-15"},
+		// Sunday: the week still started on Monday.
+		{now: "2026-03-29 23:00", expr: "this-week", first: "2026-03-23", last: "2026-03-29"},
+		{now: "2026-03-29 23:00", expr: "this-weekend", first: "2026-03-28", last: "2026-03-29"},
+
+		// Year boundaries.
+		{now: "2026-12-31 23:30", expr: "tomorrow", first: "2027-01-01", last: "2027-01-01"},
+		{now: "2026-12-31 23:30", expr: "this-week", first: "2026-12-28", last: "2027-01-03"},
+		{now: "2026-12-31 23:30", expr: "next-month", first: "2027-01-01", last: "2027-01-31"},
+		{now: "2027-01-01 00:30", expr: "last-month", first: "2026-12-01", last: "2026-12-31"},
+
+		// ISO weeks. 2020 and 2026 have 53 weeks, 2025 has 52.
+		{now: "2026-03-25 10:00", expr: "2026-W13", first: "2026-03-23", last: "2026-03-29"},
+		{now: "2026-03-25 10:00", expr: "2026w1", first: "2025-12-29", last: "2026-01-04"},
+		{now: "2026-03-25 10:00", expr: "2025-W01", first: "2024-12-30", last: "2025-01-05"},
+		{now: "2026-03-25 10:00", expr: "2026-W53", first: "2026-12-28", last: "2027-01-03"},
+		{now: "2026-03-25 10:00", expr: "2020-W53", first: "2020-12-28", last: "2021-01-03"},
+		{now: "2026-03-25 10:00", expr: "2027-W01", first: "2027-01-04", last: "2027-01-10"},
+		{now: "2026-03-25 10:00", expr: "2025-W53", wantErrSubstr: "2025 has 52 weeks"},
+		{now: "2026-03-25 10:00", expr: "2026-W00", wantErrSubstr: "invalid ISO week"},
+
+		{now: "2026-03-25 10:00", expr: "2026-13", wantErrSubstr: "invalid month"},
+		{now: "2026-03-25 10:00", expr: "someday", wantErrSubstr: "invalid date expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now+" "+tt.expr, func(t *testing.T) {
+			r, err := ParseDateExpr(tt.expr, at(tt.now), false)
+			if tt.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %v, want %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.FirstDay() != tt.first || r.LastDay() != tt.last {
+				t.Errorf("got %s .. %s, want %s .. %s", r.FirstDay(), r.LastDay(), tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestParseDateExprPoints(t *testing.T) {
+	now := at("2026-03-25 10:00")
+	tests := []struct {
+		expr string
+		past bool
+		want string
+	}{
+		{expr: "+2w", want: "2026-04-08T10:00:00+02:00"},
+		{expr: "-3d", want: "2026-03-22T10:00:00+01:00"},
+		{expr: "3d", want: "2026-03-28T10:00:00+01:00"},
+		{expr: "3d", past: true, want: "2026-03-22T10:00:00+01:00"},
+		{expr: "+3d", past: true, want: "2026-03-28T10:00:00+01:00"},
+		{expr: "+1mo", want: "2026-04-25T10:00:00+02:00"},
+		{expr: "1y", past: true, want: "2025-03-25T10:00:00+01:00"},
+		{expr: "now", want: "2026-03-25T10:00:00+01:00"},
+		{expr: "2026-03-15T20:00", want: "2026-03-15T20:00:00+01:00"},
+		{expr: "2026-07-15 20:00:30", want: "2026-07-15T20:00:30+02:00"},
+		{expr: "2026-03-15T20:00:00Z", want: "2026-03-15T20:00:00Z"},
+	}
+	for _, tt := range tests {
+		r, err := ParseDateExpr(tt.expr, now, tt.past)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := r.From.Format(time.RFC3339); got != tt.want || !r.To.Equal(r.From) {
+			t.Errorf("%s (past %v) = %s .. %s, want the point %s", tt.expr, tt.past, got, r.To.Format(time.RFC3339), tt.want)
+		}
+	}
+}
+
+// TestParseDateExprDST checks that days around the clock changes in
+// Europe/Amsterdam keep their calendar dates, whatever zone the clock is in.
+func TestParseDateExprDST(t *testing.T) {
+	tests := []struct {
+		now   time.Time
+		expr  string
+		first string
+		hours float64
+	}{
+		// Summer time starts on 29 March 2026: a 23 hour day.
+		{now: at("2026-03-29 12:00"), expr: "today", first: "2026-03-29", hours: 23},
+		{now: at("2026-03-28 12:00"), expr: "tomorrow", first: "2026-03-29", hours: 23},
+		// Winter time starts on 25 October 2026: a 25 hour day.
+		{now: at("2026-10-25 12:00"), expr: "today", first: "2026-10-25", hours: 25},
+		{now: at("2026-10-26 08:00"), expr: "yesterday", first: "2026-10-25", hours: 25},
+		// Weeks containing a change are a day off 168 hours.
+		{now: at("2026-03-25 10:00"), expr: "this-week", first: "2026-03-23", hours: 167},
+		{now: at("2026-10-21 10:00"), expr: "this-week", first: "2026-10-19", hours: 169},
+		// A UTC clock just before midnight is already the next day in Amsterdam.
+		{now: time.Date(2026, 3, 28, 23, 30, 0, 0, time.UTC), expr: "today", first: "2026-03-29", hours: 23},
+		{now: time.Date(2026, 10, 24, 22, 30, 0, 0, time.UTC), expr: "today", first: "2026-10-25", hours: 25},
+	}
+	for _, tt := range tests {
+		r, err := ParseDateExpr(tt.expr, tt.now, false)
+		if err != nil {
+			t.Errorf("%s at %s: %v", tt.expr, tt.now, err)
+			continue
+		}
+		if r.FirstDay() != tt.first || r.To.Sub(r.From).Hours() != tt.hours {
+			t.Errorf("%s at %s = %s lasting %v, want %s lasting %vh", tt.expr, tt.now, r.FirstDay(), r.To.Sub(r.From), tt.first, tt.hours)
+		}
+	}
+}
+
+func TestParseRangeExpr(t *testing.T) {
+	now := at("2026-03-25 10:00")
+	tests := []struct {
+		expr          string
+		first, last   string
+		wantErrSubstr string
+	}{
+		{expr: "today..+2w", first: "2026-03-25", last: "2026-04-08"},
+		{expr: "+2w", first: "2026-03-25", last: "2026-04-08"},
+		{expr: "-3d", first: "2026-03-22", last: "2026-03-25"},
+		{expr: "2026-03-01..2026-03-31", first: "2026-03-01", last: "2026-03-31"},
+		{expr: "next-week..next-month", first: "2026-03-30", last: "2026-04-30"},
+		{expr: "2026-W52..2027-W01", first: "2026-12-21", last: "2027-01-10"},
+		{expr: "this-weekend", first: "2026-03-28", last: "2026-03-29"},
+		{expr: "today..2026-03-29T20:00", first: "2026-03-25", last: "2026-03-29"},
+		{expr: "+1w..today", wantErrSubstr: "end is before start"},
+		{expr: "today..someday", wantErrSubstr: "invalid date expression"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRangeExpr(tt.expr, now)
+		if tt.wantErrSubstr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+				t.Errorf("%s: error %v, want %q", tt.expr, err, tt.wantErrSubstr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if r.FirstDay() != tt.first || r.LastDay() != tt.last {
+			t.Errorf("%s = %s .. %s, want %s .. %s", tt.expr, r.FirstDay(), r.LastDay(), tt.first, tt.last)
+		}
+	}
+}
+
+func TestEventDateRange(t *testing.T) {
+	fixClock(t, at("2026-03-25 10:00"))
+	tests := []struct {
+		when, from, to string
+		wantFrom       string
+		wantTo         string
+		wantErrSubstr  string
+	}{
+		{},
+		{when: "this-weekend", wantFrom: "2026-03-28", wantTo: "2026-03-29"},
+		{when: "+1w", wantFrom: "2026-03-25", wantTo: "2026-04-01"},
+		{when: "2026-W53", wantFrom: "2026-12-28", wantTo: "2027-01-03"},
+		{from: "today", to: "next-month", wantFrom: "2026-03-25", wantTo: "2026-04-30"},
+		{to: "+1w", wantTo: "2026-04-01"},
+		{from: "2026-W14", wantFrom: "2026-03-30"},
+		{to: "2026-W14", wantTo: "2026-04-05"},
+		{when: "today", from: "today", wantErrSubstr: "cannot be combined"},
+		{when: "someday", wantErrSubstr: "--when:"},
+		{from: "someday", wantErrSubstr: "--date-from:"},
+		{to: "2025-W53", wantErrSubstr: "--date-to:"},
+	}
+	for _, tt := range tests {
+		from, to, err := eventDateRange(tt.when, tt.from, tt.to)
+		name := "--when=" + tt.when + " --date-from=" + tt.from + " --date-to=" + tt.to
+		if tt.wantErrSubstr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+				t.Errorf("%s: error %v, want %q", name, err, tt.wantErrSubstr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if from != tt.wantFrom || to != tt.wantTo {
+			t.Errorf("%s = %q, %q; want %q, %q", name, from, to, tt.wantFrom, tt.wantTo)
+		}
+	}
+}
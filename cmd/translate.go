@@ -0,0 +1,461 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// translatableFields lists the TRCItemDetail fields filled in by translate, in display order.
+var translatableFields = []string{"title", "shortdescription", "longdescription"}
+
+// TranslateFlags are shared by the translate command of every resource type.
+type TranslateFlags struct {
+	IDs        []string `arg:"" optional:"" help:"IDs of the resources to translate. Alternatively select resources with --where."`
+	Where      []string `sep:"none" help:"Select resources with a list filter instead of IDs, as key=value. Repeatable. Keys: search, markers, keywords, types, categories, wfstatus, published, owner, userorganisation, trcid, externalid, lang, updated-since. Example: --where wfstatus=approved --where markers=featured."`
+	To         string   `required:"" help:"Comma-separated target languages to fill in (e.g. en,de)."`
+	From       string   `help:"Source language to translate from. Default: the resource's primary language, or the first of nl, en, de that has content."`
+	Translator string   `env:"FF_TRANSLATOR" help:"Translator command with its arguments. It receives a JSON request on stdin and must write a JSON response on stdout. Quote paths and arguments with spaces as in a shell, e.g. '\"/opt/my tools/translate\" --model fast'. See 'Machine Translation' in the README for the format."`
+	Overwrite  bool     `help:"Replace existing translations. By default only empty fields are filled in, so human translations are never overwritten."`
+	DryRun     bool     `name:"dry-run" help:"Show the proposed translations without saving them."`
+	Force      bool     `short:"f" help:"Save without a confirmation prompt for each resource."`
+}
+
+// translationRequest is written as JSON to the translator command's stdin.
+type translationRequest struct {
+	Source string            `json:"source"`
+	Target string            `json:"target"`
+	Texts  map[string]string `json:"texts"`
+}
+
+// translationResponse is read as JSON from the translator command's stdout.
+// Texts has the same keys as the request.
+type translationResponse struct {
+	Texts map[string]string `json:"texts"`
+}
+
+// translator turns texts in one language into another.
+type translator interface {
+	Translate(ctx context.Context, req translationRequest) (map[string]string, error)
+}
+
+// commandTranslator runs an external command for every request.
+type commandTranslator struct {
+	args []string
+}
+
+func (t *commandTranslator) Translate(ctx context.Context, req translationRequest) (map[string]string, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling translation request: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.args[0], t.args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("running translator: %w", err)
+		}
+		return nil, fmt.Errorf("running translator: %w: %s", err, msg)
+	}
+
+	var resp translationResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("parsing translator output: %w", err)
+	}
+	for field := range req.Texts {
+		if strings.TrimSpace(resp.Texts[field]) == "" {
+			return nil, fmt.Errorf("translator returned no text for %q", field)
+		}
+	}
+	return resp.Texts, nil
+}
+
+// proposedTranslation holds the machine-translated fields for one target language.
+type proposedTranslation struct {
+	Lang   string
+	Fields map[string]string
+}
+
+func (f *TranslateFlags) run(ctx context.Context, client *feedfactory.Client, endpoint, noun string) error {
+	if f.Translator == "" {
+		return fmt.Errorf("no translator configured: use --translator or set FF_TRANSLATOR")
+	}
+	targets := splitList(f.To)
+	if len(targets) == 0 {
+		return fmt.Errorf("--to must name at least one language")
+	}
+
+	ids, err := resolveTargets(ctx, client, endpoint, f.IDs, f.Where)
+	if err != nil {
+		return err
+	}
+
+	args, err := splitCommand(f.Translator)
+	if err != nil {
+		return fmt.Errorf("--translator: %w", err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no translator configured: use --translator or set FF_TRANSLATOR")
+	}
+	var tr translator = &commandTranslator{args: args}
+
+	var translated, skipped int
+	progress := func() error {
+		return stopped(ctx, "after %d of %d %s: translated %d, skipped %d", translated+skipped, len(ids), pluralize(noun, len(ids)), translated, skipped)
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return progress()
+		}
+		body, err := client.GetResource(ctx, endpoint, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return progress()
+			}
+			return fmt.Errorf("getting %s %s: %w", noun, id, err)
+		}
+		var r feedfactory.Resource
+		if err := json.Unmarshal(body, &r); err != nil {
+			return fmt.Errorf("parsing %s %s: %w", noun, id, err)
+		}
+
+		source := f.From
+		if source == "" {
+			source = sourceLanguage(r)
+		}
+		src := r.Detail(source)
+		if src == nil {
+			fmt.Printf("%s %s: no content in source language %q, skipping.\n", capitalize(noun), id, source)
+			skipped++
+			continue
+		}
+
+		var proposals []proposedTranslation
+		for _, lang := range targets {
+			if lang == source {
+				continue
+			}
+			texts := fieldsToTranslate(src, r.Detail(lang), f.Overwrite)
+			if len(texts) == 0 {
+				continue
+			}
+			out, err := tr.Translate(ctx, translationRequest{Source: source, Target: lang, Texts: texts})
+			if err != nil {
+				if ctx.Err() != nil {
+					return progress()
+				}
+				return fmt.Errorf("translating %s %s to %s: %w", noun, id, lang, err)
+			}
+			proposals = append(proposals, proposedTranslation{Lang: lang, Fields: out})
+		}
+
+		if len(proposals) == 0 {
+			fmt.Printf("%s %s: nothing to translate.\n", capitalize(noun), id)
+			skipped++
+			continue
+		}
+
+		fmt.Printf("%s %s: %s\n", capitalize(noun), id, r.GetTitle())
+		for _, p := range proposals {
+			for _, field := range translatableFields {
+				if text, ok := p.Fields[field]; ok {
+					fmt.Printf("  %s %s (from %s): %s\n", p.Lang, field, source, truncate(text, 200))
+				}
+			}
+		}
+
+		if f.DryRun {
+			continue
+		}
+		if !f.Force && !confirm(fmt.Sprintf("Save translations for %s %s?", noun, id)) {
+			skipped++
+			continue
+		}
+
+		if err := client.ModifyResource(ctx, endpoint, id, func(doc map[string]interface{}) error {
+			applyTranslations(doc, proposals, f.Overwrite)
+			return nil
+		}); err != nil {
+			if ctx.Err() != nil {
+				return progress()
+			}
+			return fmt.Errorf("updating %s %s: %w", noun, id, err)
+		}
+
+		// The translations are saved at this point, so a failed comment must
+		// not make a re-run translate the resource again.
+		if err := client.AddComment(ctx, endpoint, id, translationComment(source, proposals)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s %s was translated, but adding the review comment failed: %v\n", noun, id, err)
+		}
+		translated++
+	}
+
+	if f.DryRun {
+		fmt.Println("\nDry run: no changes saved.")
+		return nil
+	}
+	fmt.Printf("\nTranslated %d %s, skipped %d.\n", translated, pluralize(noun, translated), skipped)
+	return nil
+}
+
+// splitCommand splits a command line into words like a shell does: words are
+// separated by spaces, single quotes keep everything literal, and double quotes
+// and backslashes escape spaces and quotes.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			word.WriteByte(s[i])
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// sourceLanguage picks the language to translate from: the primary language if it
+// has a title, otherwise the first of nl, en, de that has one.
+func sourceLanguage(r feedfactory.Resource) string {
+	if r.Translations != nil && r.Translations.PrimaryLanguage != "" {
+		if d := r.Detail(r.Translations.PrimaryLanguage); d != nil && d.Title != "" {
+			return r.Translations.PrimaryLanguage
+		}
+	}
+	for _, lang := range []string{"nl", "en", "de"} {
+		if d := r.Detail(lang); d != nil && d.Title != "" {
+			return lang
+		}
+	}
+	if len(r.TRCItemDetails) > 0 {
+		return r.TRCItemDetails[0].Lang
+	}
+	return "nl"
+}
+
+// fieldsToTranslate returns the source texts for fields that are empty in the target
+// (or all non-empty source fields when overwriting).
+func fieldsToTranslate(src, dst *feedfactory.TRCItemDetail, overwrite bool) map[string]string {
+	texts := map[string]string{}
+	for _, field := range translatableFields {
+		text := translationFields[field](src)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if !overwrite && dst != nil && strings.TrimSpace(translationFields[field](dst)) != "" {
+			continue
+		}
+		texts[field] = text
+	}
+	return texts
+}
+
+// applyTranslations writes proposals into the trcItemDetails of a raw resource
+// document. Existing non-empty fields are kept unless overwrite is set, so a human
+// translation saved in the meantime is not lost.
+func applyTranslations(doc map[string]interface{}, proposals []proposedTranslation, overwrite bool) {
+	details, _ := doc["trcItemDetails"].([]interface{})
+	for _, p := range proposals {
+		var detail map[string]interface{}
+		for _, d := range details {
+			if m, ok := d.(map[string]interface{}); ok && m["lang"] == p.Lang {
+				detail = m
+				break
+			}
+		}
+		if detail == nil {
+			detail = map[string]interface{}{"lang": p.Lang}
+			details = append(details, detail)
+		}
+		for field, text := range p.Fields {
+			if existing, _ := detail[field].(string); existing != "" && !overwrite {
+				continue
+			}
+			detail[field] = text
+		}
+	}
+	doc["trcItemDetails"] = details
+
+	translations, _ := doc["translations"].(map[string]interface{})
+	if translations == nil {
+		translations = map[string]interface{}{}
+	}
+	available := map[string]bool{}
+	var langs []string
+	if existing, ok := translations["availableLanguages"].([]interface{}); ok {
+		for _, l := range existing {
+			if s, ok := l.(string); ok && !available[s] {
+				available[s] = true
+				langs = append(langs, s)
+			}
+		}
+	}
+	for _, p := range proposals {
+		if !available[p.Lang] {
+			available[p.Lang] = true
+			langs = append(langs, p.Lang)
+		}
+	}
+	translations["availableLanguages"] = langs
+	doc["translations"] = translations
+}
+
+func translationComment(source string, proposals []proposedTranslation) string {
+	var parts []string
+	for _, p := range proposals {
+		fields := make([]string, 0, len(p.Fields))
+		for field := range p.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		parts = append(parts, fmt.Sprintf("%s (%s)", p.Lang, strings.Join(fields, ", ")))
+	}
+	return fmt.Sprintf("Machine-translated from %s: %s. Please review.", source, strings.Join(parts, "; "))
+}
+
+// resolveTargets returns the resource IDs a bulk command operates on: the given IDs,
+// or all resources matching the --where filter.
+func resolveTargets(ctx context.Context, client *feedfactory.Client, endpoint string, ids, where []string) ([]string, error) {
+	if len(ids) > 0 && len(where) > 0 {
+		return nil, fmt.Errorf("give either resource IDs or --where, not both")
+	}
+	if len(ids) > 0 {
+		return ids, nil
+	}
+	if len(where) == 0 {
+		return nil, fmt.Errorf("no resources selected: give one or more IDs or a --where filter")
+	}
+
+	opts, err := parseWhere(where)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := fetchAllResources(ctx, client, endpoint, opts, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("--where matched no resources")
+	}
+	return ids, nil
+}
+
+// parseWhere turns key=value filter expressions into list options.
+func parseWhere(where []string) (feedfactory.ListOptions, error) {
+	var opts feedfactory.ListOptions
+	for _, expr := range where {
+		key, value, ok := strings.Cut(expr, "=")
+		if !ok {
+			return opts, fmt.Errorf("--where %q: expected key=value", expr)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "search":
+			opts.Search = value
+		case "markers":
+			opts.Markers = value
+		case "keywords":
+			opts.Keywords = value
+		case "types":
+			opts.Types = value
+		case "categories":
+			opts.Categories = value
+		case "wfstatus":
+			opts.WFStatus = value
+		case "published":
+			opts.Published = value
+		case "owner":
+			opts.Owner = value
+		case "userorganisation":
+			opts.UserOrg = value
+		case "trcid":
+			opts.TRCID = value
+		case "externalid":
+			opts.ExternalID = value
+		case "lang":
+			opts.Language = value
+		case "updated-since":
+			iso, err := ParseRelativeISO(value)
+			if err != nil {
+				return opts, fmt.Errorf("--where updated-since: %w", err)
+			}
+			opts.UpdatedSince = iso
+		default:
+			return opts, fmt.Errorf("--where: unknown filter key %q", key)
+		}
+	}
+	return opts, nil
+}
+
+// confirm asks a yes/no question on stdout and reports whether the answer was yes.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	var answer string
+	fmt.Scanln(&answer)
+	return strings.ToLower(answer) == "y"
+}
+
+func pluralize(noun string, n int) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
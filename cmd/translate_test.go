@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"translate", []string{"translate"}},
+		{"  ./tr.sh   --model fast ", []string{"./tr.sh", "--model", "fast"}},
+		{`"/opt/my tools/translate" --model x`, []string{"/opt/my tools/translate", "--model", "x"}},
+		{`'/opt/my tools/tr' 'a "b"'`, []string{"/opt/my tools/tr", `a "b"`}},
+		{`/opt/my\ tools/tr "say \"hi\""`, []string{"/opt/my tools/tr", `say "hi"`}},
+		{`tr --empty ""`, []string{"tr", "--empty", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{`"open`, `'open`} {
+		if _, err := splitCommand(in); err == nil {
+			t.Errorf("splitCommand(%q): expected an error", in)
+		}
+	}
+}
+
+// stubTranslator writes a translator script that prefixes every text with the
+// upper-cased target language, in a directory whose name has a space.
+func stubTranslator(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub translator is a shell script")
+	}
+	dir := filepath.Join(t.TempDir(), "my tools")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "translate.sh")
+	// The request is compact JSON: drop everything before "texts" and prefix
+	// every value.
+	body := "#!/bin/sh\nsed -e 's/^.*\"texts\":/{\"texts\":/' -e 's/\":\"/\":\"EN /g'\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestTranslateWithStubTranslator(t *testing.T) {
+	script := stubTranslator(t)
+
+	var mu sync.Mutex
+	var saved, comments []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/events/E1":
+			w.Write([]byte(`{"id":"E1","trcItemDetails":[{"lang":"nl","title":"Zomerconcert","shortdescription":"Buiten"}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/events/E1":
+			saved = append(saved, string(body))
+		case r.Method == http.MethodPost && r.URL.Path == "/events/E1/comments":
+			comments = append(comments, string(body))
+			// A failing comment must not fail the run: the translation is saved.
+			http.Error(w, `{"message":"comments unavailable"}`, http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	f := &TranslateFlags{
+		IDs:        []string{"E1"},
+		To:         "en",
+		Translator: `"` + script + `"`,
+		Force:      true,
+	}
+	if err := f.run(context.Background(), client, "events", "event"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if len(saved) != 1 {
+		t.Fatalf("saved %d times, want 1", len(saved))
+	}
+	for _, want := range []string{`"title":"EN Zomerconcert"`, `"shortdescription":"EN Buiten"`, `"availableLanguages":["en"]`} {
+		if !strings.Contains(saved[0], want) {
+			t.Errorf("saved document lacks %s:\n%s", want, saved[0])
+		}
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "Machine-translated from nl") {
+		t.Errorf("comments = %q", comments)
+	}
+}
+
+func TestTranslateDryRunSavesNothing(t *testing.T) {
+	script := stubTranslator(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			return
+		}
+		w.Write([]byte(`{"id":"E1","trcItemDetails":[{"lang":"nl","title":"Zomerconcert"},{"lang":"en","title":"Summer concert"}]}`))
+	}))
+	defer srv.Close()
+	client := feedfactory.New(feedfactory.WithBaseURL(srv.URL))
+
+	f := &TranslateFlags{IDs: []string{"E1"}, To: "en,de", Translator: `"` + script + `"`, DryRun: true}
+	if err := f.run(context.Background(), client, "events", "event"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 )
 
 func printJSON(v interface{}) error {
+	if querying() {
+		return printQuery(v, "json")
+	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(v); err != nil {
@@ -16,6 +20,9 @@ func printJSON(v interface{}) error {
 }
 
 func printRawJSON(data []byte) error {
+	if querying() {
+		return printQuery(json.RawMessage(data), "json")
+	}
 	var v interface{}
 	if err := json.Unmarshal(data, &v); err != nil {
 		return fmt.Errorf("parsing JSON: %w", err)
@@ -40,3 +47,26 @@ func boolYesNo(b bool) string {
 	return "No"
 }
 
+// underline returns a row of dashes matching the width of each header.
+func underline(headers []string) []string {
+	out := make([]string, len(headers))
+	for i, h := range headers {
+		out[i] = strings.Repeat("-", len(h))
+	}
+	return out
+}
+
+// createdID returns the ID of a resource in the response to a create. A
+// response without one is an error: the resource may or may not exist.
+func createdID(data []byte) (string, error) {
+	var res struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return "", fmt.Errorf("decoding created resource: %w", err)
+	}
+	if res.ID == "" {
+		return "", fmt.Errorf("created resource has no id: %s", truncate(string(data), 200))
+	}
+	return res.ID, nil
+}
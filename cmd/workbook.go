@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+type ReportWorkbookCmd struct {
+	Types  []string `arg:"" optional:"" help:"Resource types to include: events, locations, routes, venues, eventgroups. Default: all types."`
+	File   string   `name:"file" short:"o" required:"" help:"Output file path (.xlsx)."`
+	Sheets string   `enum:"type,status" default:"type" help:"One sheet per resource type (default) or per workflow status. Sheets per status start with a resource column naming the type."`
+	ScanFlags
+}
+
+// workbookStatuses is the order of the workflow status sheets and summary columns.
+// Statuses the API adds later follow in alphabetical order.
+var workbookStatuses = []string{"draft", "readyforvalidation", "approved", "rejected", "deleted", "archived"}
+
+// workbookColumn is a column of 'report workbook'.
+type workbookColumn struct {
+	xlsxColumn
+	// types are the endpoints of the resource types that have the column;
+	// nil means all types.
+	types map[string]bool
+	value func(endpoint string, r resourceRow) interface{}
+}
+
+// workbookRow is a resource in the workbook with the endpoint of its type.
+type workbookRow struct {
+	resourceRow
+	endpoint string
+}
+
+func (c workbookColumn) has(endpoint string) bool {
+	return c.types == nil || c.types[endpoint]
+}
+
+// workbookLinkColumns are the columns only the workbook has.
+var workbookLinkColumns = []workbookColumn{
+	{
+		xlsxColumn: xlsxColumn{Name: "website", Kind: xlsxLink},
+		value: func(_ string, r resourceRow) interface{} {
+			if r.ContactInfo != nil && len(r.ContactInfo.URLs) > 0 {
+				return r.ContactInfo.URLs[0].URL
+			}
+			return nil
+		},
+	},
+	{
+		xlsxColumn: xlsxColumn{Name: "image", Kind: xlsxLink},
+		value: func(_ string, r resourceRow) interface{} {
+			for _, m := range r.Media {
+				if m.Main {
+					return m.URL
+				}
+			}
+			if len(r.Media) > 0 {
+				return r.Media[0].URL
+			}
+			return nil
+		},
+	},
+}
+
+func (c *ReportWorkbookCmd) Run(ctx context.Context, client *feedfactory.Client) error {
+	if strings.ToLower(filepath.Ext(c.File)) != ".xlsx" {
+		return fmt.Errorf("--file must be an .xlsx file")
+	}
+	types, err := c.scanTypes(c.Types)
+	if err != nil {
+		return err
+	}
+	columns, err := workbookColumns(types)
+	if err != nil {
+		return err
+	}
+
+	rows := map[string][]workbookRow{}
+	total := 0
+	for _, t := range types {
+		resources, err := c.scan(ctx, client, t)
+		if err != nil {
+			return fmt.Errorf("scanning %s: %w", t, err)
+		}
+		for _, r := range resources {
+			rows[t] = append(rows[t], workbookRow{resourceRow: resourceRow{Resource: r}, endpoint: t})
+		}
+		total += len(resources)
+	}
+
+	sheets := []xlsxSheet{workbookSummary(types, rows)}
+	if c.Sheets == "status" {
+		sheets = append(sheets, workbookStatusSheets(types, rows, columns)...)
+	} else {
+		for _, t := range types {
+			sheets = append(sheets, workbookSheet(descriptorFor(t).Plural, rows[t], columns, t, false))
+		}
+	}
+
+	err = writeAtomic(c.File, func(w io.Writer) error {
+		return writeWorkbook(w, sheets)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d resources in %d sheets to %s\n", total, len(sheets), c.File)
+	return nil
+}
+
+// workbookColumns returns the columns for the given resource types: the list
+// columns of all types (or those selected with --columns) followed by the
+// link columns. Dates and timestamps become date cells.
+func workbookColumns(types []string) ([]workbookColumn, error) {
+	var columns []workbookColumn
+	index := map[string]int{}
+	for _, t := range types {
+		for _, col := range descriptorFor(t).Columns {
+			if i, ok := index[col.Name]; ok {
+				columns[i].types[t] = true
+				continue
+			}
+			index[col.Name] = len(columns)
+			columns = append(columns, workbookListColumn(col, t))
+		}
+	}
+	for _, col := range workbookLinkColumns {
+		index[col.Name] = len(columns)
+		columns = append(columns, col)
+	}
+
+	if len(outputOpts.Columns) == 0 {
+		return columns, nil
+	}
+	selected := make([]workbookColumn, 0, len(outputOpts.Columns))
+	for _, name := range outputOpts.Columns {
+		i, ok := index[name]
+		if !ok {
+			names := make([]string, len(columns))
+			for j, col := range columns {
+				names[j] = col.Name
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+// workbookListColumn turns the list column col of the resource type t into a
+// workbook column.
+func workbookListColumn(col column[resourceRow], t string) workbookColumn {
+	wc := workbookColumn{
+		xlsxColumn: xlsxColumn{Name: col.Name},
+		types:      map[string]bool{t: true},
+	}
+	switch col.Name {
+	case "date":
+		wc.Kind = xlsxDate
+		wc.value = func(_ string, r resourceRow) interface{} { return workbookDate(r.GetFirstDate()) }
+	case "created":
+		wc.Kind = xlsxDateTime
+		wc.value = func(_ string, r resourceRow) interface{} { return workbookTime(r.Created) }
+	case "updated":
+		wc.Kind = xlsxDateTime
+		wc.value = func(_ string, r resourceRow) interface{} { return workbookTime(r.LastUpdated) }
+	default:
+		if col.Width > 20 {
+			wc.Width = float64(col.Width)
+		}
+		// Types may compute a column of the same name differently.
+		wc.value = func(endpoint string, r resourceRow) interface{} {
+			for _, c := range descriptorFor(endpoint).Columns {
+				if c.Name == col.Name {
+					return c.Value(r)
+				}
+			}
+			return nil
+		}
+	}
+	return wc
+}
+
+// workbookDate returns an event date (yyyy-mm-dd, possibly followed by a time)
+// as a date cell value, or the text itself when it is not a date.
+func workbookDate(s string) interface{} {
+	if len(s) >= len(dateLayout) {
+		if t, err := time.ParseInLocation(dateLayout, s[:len(dateLayout)], apiLocation); err == nil {
+			return t
+		}
+	}
+	return s
+}
+
+// workbookTime returns a timestamp in the display time zone, or the text it was
+// read from when it could not be parsed.
+func workbookTime(t feedfactory.Timestamp) interface{} {
+	if t.Time.IsZero() {
+		return t.Display(displayLocation)
+	}
+	return t.Time.In(displayLocation)
+}
+
+// workbookSheet returns a sheet with the rows of resource type t. With typeColumn
+// the rows are of several types and the first column names their type.
+func workbookSheet(name string, rows []workbookRow, columns []workbookColumn, t string, typeColumn bool) xlsxSheet {
+	sheet := xlsxSheet{Name: name}
+	if typeColumn {
+		sheet.Columns = append(sheet.Columns, xlsxColumn{Name: "resource", Width: 12})
+	}
+	var cols []workbookColumn
+	for _, col := range columns {
+		if typeColumn || col.has(t) {
+			cols = append(cols, col)
+			sheet.Columns = append(sheet.Columns, col.xlsxColumn)
+		}
+	}
+
+	for _, r := range rows {
+		var row []interface{}
+		if typeColumn {
+			row = append(row, descriptorFor(r.endpoint).Singular)
+		}
+		for _, col := range cols {
+			if col.has(r.endpoint) {
+				row = append(row, col.value(r.endpoint, r.resourceRow))
+			} else {
+				row = append(row, nil)
+			}
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet
+}
+
+// workbookStatusSheets returns a sheet per workflow status with the resources of
+// all types in that status. Statuses without resources are left out.
+func workbookStatusSheets(types []string, rows map[string][]workbookRow, columns []workbookColumn) []xlsxSheet {
+	byStatus := map[string][]workbookRow{}
+	seen := map[string]bool{}
+	for _, t := range types {
+		for _, r := range rows[t] {
+			byStatus[r.WFStatus] = append(byStatus[r.WFStatus], r)
+			seen[r.WFStatus] = true
+		}
+	}
+
+	var sheets []xlsxSheet
+	for _, status := range orderedStatuses(seen) {
+		name := status
+		if name == "" {
+			name = "no status"
+		}
+		sheets = append(sheets, workbookSheet(name, byStatus[status], columns, "", true))
+	}
+	return sheets
+}
+
+// workbookSummary returns the summary sheet: per type the number of resources in
+// each workflow status, the number published and the total.
+func workbookSummary(types []string, rows map[string][]workbookRow) xlsxSheet {
+	counts := map[string]map[string]int{}
+	seen := map[string]bool{}
+	for _, t := range types {
+		counts[t] = map[string]int{}
+		for _, r := range rows[t] {
+			counts[t][r.WFStatus]++
+			seen[r.WFStatus] = true
+		}
+	}
+	statuses := orderedStatuses(seen)
+
+	sheet := xlsxSheet{Name: "Summary", Columns: []xlsxColumn{{Name: "type", Width: 14}}}
+	for _, s := range statuses {
+		name := s
+		if name == "" {
+			name = "no status"
+		}
+		sheet.Columns = append(sheet.Columns, xlsxColumn{Name: name, Kind: xlsxNumber, Width: float64(max(len(name)+2, 10))})
+	}
+	sheet.Columns = append(sheet.Columns,
+		xlsxColumn{Name: "published", Kind: xlsxNumber},
+		xlsxColumn{Name: "total", Kind: xlsxNumber},
+	)
+
+	totals := make([]int, len(statuses)+2)
+	for _, t := range types {
+		row := []interface{}{descriptorFor(t).Plural}
+		published := 0
+		for _, r := range rows[t] {
+			if r.Published {
+				published++
+			}
+		}
+		for i, s := range statuses {
+			row = append(row, counts[t][s])
+			totals[i] += counts[t][s]
+		}
+		row = append(row, published, len(rows[t]))
+		totals[len(statuses)] += published
+		totals[len(statuses)+1] += len(rows[t])
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	row := []interface{}{"total"}
+	for _, n := range totals {
+		row = append(row, n)
+	}
+	generated := time.Now().In(displayLocation).Format("2006-01-02 15:04 MST")
+	sheet.Rows = append(sheet.Rows, row, nil, []interface{}{"generated", generated})
+	return sheet
+}
+
+// orderedStatuses returns the statuses in seen in workbookStatuses order,
+// followed by unknown statuses in alphabetical order.
+func orderedStatuses(seen map[string]bool) []string {
+	var statuses, other []string
+	for _, s := range workbookStatuses {
+		if seen[s] {
+			statuses = append(statuses, s)
+		}
+	}
+	for s := range seen {
+		if !contains(workbookStatuses, s) {
+			other = append(other, s)
+		}
+	}
+	sort.Strings(other)
+	return append(statuses, other...)
+}
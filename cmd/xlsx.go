@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxKind is how the cells of a workbook column are written.
+type xlsxKind int
+
+const (
+	xlsxText xlsxKind = iota
+	// xlsxNumber columns are right-aligned counts.
+	xlsxNumber
+	// xlsxDate and xlsxDateTime columns hold time.Time values, shown as a
+	// date or as date and time in the time zone of the value.
+	xlsxDate
+	xlsxDateTime
+	// xlsxLink columns hold URLs, written as clickable hyperlinks.
+	xlsxLink
+)
+
+// xlsxColumn is a column of a worksheet.
+type xlsxColumn struct {
+	Name string
+	Kind xlsxKind
+	// Width is the column width in characters; 0 picks one for the kind.
+	Width float64
+}
+
+// xlsxSheet is a worksheet written by writeWorkbook. Each row has a value per
+// column; nil leaves the cell empty.
+type xlsxSheet struct {
+	Name    string
+	Columns []xlsxColumn
+	Rows    [][]interface{}
+}
+
+// xlsxStyles are the cell styles of a workbook.
+type xlsxStyles struct {
+	header, date, dateTime, link int
+}
+
+// writeWorkbook writes sheets as an Excel workbook to w, in order. Every sheet
+// gets a bold header row that stays in view while scrolling.
+func writeWorkbook(w io.Writer, sheets []xlsxSheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return fmt.Errorf("creating workbook styles: %w", err)
+	}
+
+	for i, sheet := range sheets {
+		name := xlsxSheetName(sheet.Name)
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), name); err != nil {
+				return fmt.Errorf("creating sheet %q: %w", name, err)
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return fmt.Errorf("creating sheet %q: %w", name, err)
+		}
+		if err := writeSheet(f, name, sheet, styles); err != nil {
+			return fmt.Errorf("writing sheet %q: %w", name, err)
+		}
+	}
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("writing workbook: %w", err)
+	}
+	return nil
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var s xlsxStyles
+	var err error
+	s.header, err = f.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"305496"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "1F3864", Style: 2}},
+	})
+	if err != nil {
+		return s, err
+	}
+	dateFmt, dateTimeFmt := "yyyy-mm-dd", "yyyy-mm-dd hh:mm"
+	if s.date, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt}); err != nil {
+		return s, err
+	}
+	if s.dateTime, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateTimeFmt}); err != nil {
+		return s, err
+	}
+	s.link, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}})
+	return s, err
+}
+
+func writeSheet(f *excelize.File, name string, sheet xlsxSheet, styles xlsxStyles) error {
+	sw, err := f.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	// Panes and widths must be set before the first row.
+	err = sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	if err != nil {
+		return err
+	}
+	for i, col := range sheet.Columns {
+		if err := sw.SetColWidth(i+1, i+1, xlsxWidth(col)); err != nil {
+			return err
+		}
+	}
+
+	header := make([]interface{}, len(sheet.Columns))
+	for i, col := range sheet.Columns {
+		header[i] = excelize.Cell{StyleID: styles.header, Value: col.Name}
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for i, row := range sheet.Rows {
+		cells := make([]interface{}, len(row))
+		for j, v := range row {
+			kind := xlsxText
+			if j < len(sheet.Columns) {
+				kind = sheet.Columns[j].Kind
+			}
+			cells[j] = xlsxCell(v, kind, styles)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := sw.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+	return sw.Flush()
+}
+
+// xlsxCell returns the cell written for value v in a column of the given kind.
+func xlsxCell(v interface{}, kind xlsxKind, styles xlsxStyles) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		// Longer URLs don't fit in a HYPERLINK formula and stay plain text.
+		if kind == xlsxLink && len(v) <= 255 && (strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://")) {
+			formula := `HYPERLINK("` + strings.ReplaceAll(v, `"`, `""`) + `")`
+			return excelize.Cell{StyleID: styles.link, Formula: formula, Value: v}
+		}
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		if kind == xlsxDate {
+			return excelize.Cell{StyleID: styles.date, Value: v}
+		}
+		return excelize.Cell{StyleID: styles.dateTime, Value: v}
+	}
+	return v
+}
+
+// xlsxWidth returns the width of a column.
+func xlsxWidth(col xlsxColumn) float64 {
+	if col.Width > 0 {
+		return col.Width
+	}
+	switch col.Kind {
+	case xlsxNumber, xlsxDate:
+		return 12
+	case xlsxDateTime:
+		return 17
+	case xlsxLink:
+		return 40
+	}
+	return 20
+}
+
+// xlsxSheetName makes name valid as a sheet name: at most 31 characters and
+// none of : \ / ? * [ ].
+func xlsxSheetName(name string) string {
+	out := make([]rune, 0, len(name))
+	for _, r := range name {
+		switch r {
+		case ':', '\\', '/', '?', '*', '[', ']':
+			r = '-'
+		}
+		out = append(out, r)
+		if len(out) == 31 {
+			break
+		}
+	}
+	if len(out) == 0 {
+		return "Sheet1"
+	}
+	return string(out)
+}
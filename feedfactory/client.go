@@ -0,0 +1,848 @@
+package feedfactory
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the FeedFactory API endpoint used unless WithBaseURL is given.
+const DefaultBaseURL = "https://app.thefeedfactory.nl/api"
+
+// Client is a FeedFactory API client. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	tokens     TokenSource
+	userAgent  string
+	logger     *slog.Logger
+}
+
+// New returns a client configured by opts. Without options it talks to
+// DefaultBaseURL without an access token.
+func New(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{},
+		tokens:     StaticToken(""),
+		userAgent:  "feedfactory-go",
+		logger:     slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned when the API responds with an error status.
+type APIError struct {
+	StatusCode int
+	// Message is the error message from the response, or the response body when
+	// it has none.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body io.Reader) ([]byte, error) {
+	resp, err := c.send(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	return respBody, nil
+}
+
+// send performs a request and returns the response with its body unread. Error
+// statuses are returned as *APIError, with the body already closed.
+func (c *Client) send(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+	reqURL := c.baseURL + endpoint
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting access token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.DebugContext(ctx, "request failed", "method", method, "path", endpoint, "error", err)
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	c.logger.DebugContext(ctx, "request", "method", method, "path", endpoint, "status", resp.StatusCode, "duration", time.Since(start))
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		var errMsg string
+		var errResp map[string]interface{}
+		if err := json.Unmarshal(respBody, &errResp); err == nil {
+			if msg, ok := errResp["message"].(string); ok && msg != "" {
+				errMsg = msg
+			} else if msg, ok := errResp["error"].(string); ok && msg != "" {
+				errMsg = msg
+			}
+		}
+		if errMsg == "" {
+			errMsg = string(respBody)
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: errMsg}
+	}
+
+	return resp, nil
+}
+
+// FetchURL gets a URL outside the API, such as an image a media item links to,
+// with the HTTP client and User-Agent of the client. The access token is not
+// sent. The caller closes the body of the response.
+func (c *Client) FetchURL(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.DebugContext(ctx, "request failed", "method", http.MethodGet, "url", rawURL, "error", err)
+		return nil, err
+	}
+	c.logger.DebugContext(ctx, "request", "method", http.MethodGet, "url", rawURL, "status", resp.StatusCode, "duration", time.Since(start))
+	return resp, nil
+}
+
+// SearchResult represents the paginated response from list endpoints.
+type SearchResult struct {
+	Size    int               `json:"size"`
+	Page    int               `json:"page"`
+	Hits    int               `json:"hits"`
+	Results []json.RawMessage `json:"results"`
+}
+
+type Comment struct {
+	ID      string    `json:"id"`
+	Text    string    `json:"text"`
+	Author  string    `json:"author"`
+	Created Timestamp `json:"created"`
+}
+
+type Revision struct {
+	ID      string    `json:"id"`
+	Author  string    `json:"author"`
+	Created Timestamp `json:"created"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+type Account struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+}
+
+type DictionaryItem struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Value string `json:"value,omitempty"`
+}
+
+// Ontology is the categorization ontology stored on the account.
+type Ontology struct {
+	LastModified    string           `json:"lastModified"`
+	Categorizations []Categorization `json:"categorizations"`
+}
+
+// Categorization is a node in the ontology tree. Leaf nodes are the categories that
+// can be assigned to resources.
+type Categorization struct {
+	CnetID       string           `json:"cnetID"`
+	Name         string           `json:"categorization"`
+	ID           *string          `json:"categorizationId"`
+	EntityType   string           `json:"entityType"`
+	Deprecated   interface{}      `json:"deprecated"`
+	Children     []Categorization `json:"child"`
+	Translations []CatTranslation `json:"categoryTranslations"`
+}
+
+type CatTranslation struct {
+	Lang  string `json:"lang"`
+	Label string `json:"label"`
+}
+
+// IsDeprecated reports whether the category is marked deprecated. The API uses
+// either a boolean or a non-empty string (the deprecation date) for this.
+func (c *Categorization) IsDeprecated() bool {
+	switch v := c.Deprecated.(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	return false
+}
+
+// DeprecatedIDs returns the cnetIDs and categorization IDs of all deprecated
+// categories, including children of deprecated categories.
+func (o *Ontology) DeprecatedIDs() map[string]bool {
+	ids := map[string]bool{}
+	var walk func(cats []Categorization, deprecated bool)
+	walk = func(cats []Categorization, deprecated bool) {
+		for _, cat := range cats {
+			d := deprecated || cat.IsDeprecated()
+			if d {
+				if cat.CnetID != "" {
+					ids[cat.CnetID] = true
+				}
+				if cat.ID != nil && *cat.ID != "" {
+					ids[*cat.ID] = true
+				}
+			}
+			walk(cat.Children, d)
+		}
+	}
+	walk(o.Categorizations, false)
+	return ids
+}
+
+// ParseOntology parses the data returned by GetOntology.
+func ParseOntology(data []byte) (*Ontology, error) {
+	var ontology Ontology
+	if err := json.Unmarshal(data, &ontology); err != nil {
+		return nil, fmt.Errorf("parsing ontology: %w", err)
+	}
+	return &ontology, nil
+}
+
+// OntologyEntityType maps a user-friendly resource type (event, location, route,
+// eventgroup) to the entityType used in the ontology. Unknown types map to "".
+func OntologyEntityType(resourceType string) string {
+	switch strings.ToLower(resourceType) {
+	case "event", "events":
+		return "EVENEMENT"
+	case "location", "locations", "venue", "venues":
+		return "LOCATIE"
+	case "route", "routes":
+		return "ROUTE"
+	case "eventgroup", "eventgroups":
+		return "EVENEMENTGROEP"
+	default:
+		return ""
+	}
+}
+
+type Organisation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListOptions contains common query parameters for list endpoints.
+type ListOptions struct {
+	Search       string
+	Markers      string
+	Keywords     string
+	Types        string
+	Categories   string
+	WFStatus     string
+	Published    string
+	Deleted      bool
+	Owner        string
+	UserOrg      string
+	TRCID        string
+	ExternalID   string
+	Language     string
+	UpdatedSince string
+	Sort         string
+	Asc          bool
+	Size         int
+	Page         int
+	// Fields limits each result to these fields (dotted paths, e.g.
+	// trcItemDetails.title). They are sent to the API and also applied to the
+	// response, so results are trimmed even where the API returns full documents.
+	Fields []string
+}
+
+// EventListOptions extends ListOptions with event-specific parameters.
+type EventListOptions struct {
+	ListOptions
+	DateFrom    string
+	DateTo      string
+	LocationID  string
+	City        string
+	GeoLat      string
+	GeoLon      string
+	GeoDistance string
+}
+
+// Params returns the query parameters ListResources sends for opts.
+func (opts ListOptions) Params() url.Values {
+	return buildListQuery(opts)
+}
+
+func buildListQuery(opts ListOptions) url.Values {
+	q := url.Values{}
+
+	if opts.Search != "" {
+		q.Set("search", opts.Search)
+	}
+	if opts.Markers != "" {
+		q.Set("markers", opts.Markers)
+	}
+	if opts.Keywords != "" {
+		q.Set("keywords", opts.Keywords)
+	}
+	if opts.Types != "" {
+		q.Set("types", opts.Types)
+	}
+	if opts.Categories != "" {
+		q.Set("categories", opts.Categories)
+	}
+	if opts.WFStatus != "" {
+		q.Set("wfstatus", opts.WFStatus)
+	}
+	if opts.Published != "" {
+		q.Set("published", opts.Published)
+	}
+	if opts.Deleted {
+		q.Set("deleted", "true")
+	}
+	if opts.Owner != "" {
+		q.Set("owner", opts.Owner)
+	}
+	if opts.UserOrg != "" {
+		q.Set("userorganisation", opts.UserOrg)
+	}
+	if opts.TRCID != "" {
+		q.Set("trcid", opts.TRCID)
+	}
+	if opts.ExternalID != "" {
+		q.Set("externalid", opts.ExternalID)
+	}
+	if opts.Language != "" {
+		q.Set("lang", opts.Language)
+	}
+	if opts.UpdatedSince != "" {
+		q.Set("lastupdated", opts.UpdatedSince)
+	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
+	if opts.Asc {
+		q.Set("sortorder", "asc")
+	}
+	if opts.Size > 0 {
+		q.Set("size", fmt.Sprintf("%d", opts.Size))
+	}
+	if opts.Page > 0 {
+		q.Set("page", fmt.Sprintf("%d", opts.Page))
+	}
+	if len(opts.Fields) > 0 {
+		q.Set("fields", strings.Join(opts.Fields, ","))
+	}
+
+	return q
+}
+
+// Params returns the event-specific query parameters.
+func (opts EventListOptions) Params() url.Values {
+	q := url.Values{}
+	if opts.DateFrom != "" {
+		q.Set("eventDateRangeStart", opts.DateFrom)
+	}
+	if opts.DateTo != "" {
+		q.Set("eventDateRangeEnd", opts.DateTo)
+	}
+	if opts.LocationID != "" {
+		q.Set("locationId", opts.LocationID)
+	}
+	if opts.City != "" {
+		q.Set("city", opts.City)
+	}
+	if opts.GeoLat != "" && opts.GeoLon != "" {
+		q.Set("geo", opts.GeoLat+","+opts.GeoLon)
+	}
+	if opts.GeoDistance != "" {
+		q.Set("geodistance", opts.GeoDistance)
+	}
+	return q
+}
+
+// ListResources returns resources from a list endpoint (events, locations, routes,
+// venues or eventgroups) matching the given options. params adds type-specific
+// query parameters, such as EventListOptions.Params.
+func (c *Client) ListResources(ctx context.Context, resourceType string, opts ListOptions, params url.Values) (*SearchResult, error) {
+	q := buildListQuery(opts)
+	for k, v := range params {
+		q[k] = v
+	}
+
+	endpoint := "/" + resourceType + "?" + q.Encode()
+	body, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SearchResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if err := result.Project(opts.Fields); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListEvents returns events matching the given options.
+func (c *Client) ListEvents(ctx context.Context, opts EventListOptions) (*SearchResult, error) {
+	return c.ListResources(ctx, "events", opts.ListOptions, opts.Params())
+}
+
+// ListLocations returns locations matching the given options.
+func (c *Client) ListLocations(ctx context.Context, opts ListOptions) (*SearchResult, error) {
+	return c.ListResources(ctx, "locations", opts, nil)
+}
+
+// ListRoutes returns routes matching the given options.
+func (c *Client) ListRoutes(ctx context.Context, opts ListOptions) (*SearchResult, error) {
+	return c.ListResources(ctx, "routes", opts, nil)
+}
+
+// ListVenues returns venues matching the given options.
+func (c *Client) ListVenues(ctx context.Context, opts ListOptions) (*SearchResult, error) {
+	return c.ListResources(ctx, "venues", opts, nil)
+}
+
+// ListEventGroups returns event groups matching the given options.
+func (c *Client) ListEventGroups(ctx context.Context, opts ListOptions) (*SearchResult, error) {
+	return c.ListResources(ctx, "eventgroups", opts, nil)
+}
+
+// ExportOptions holds parameters specific to Excel export.
+type ExportOptions struct {
+	// PropertyIDs is a comma-separated list of category property IDs to include as
+	// additional columns in the Excel export. Each property ID maps to a category in
+	// trcItemCategories; its value is added as a column. Use 'dictionary categories'
+	// to find available IDs.
+	PropertyIDs string
+	// Format is the export format: "excel" (default) or "uitkrant" (events only, plain text).
+	Format string
+}
+
+// ExportResources exports resources from a list endpoint as a file generated by
+// the API (Excel by default) and returns it in memory. Use OpenExport for large
+// exports.
+func (c *Client) ExportResources(ctx context.Context, resourceType string, opts ListOptions, params url.Values, exportOpts ExportOptions) ([]byte, error) {
+	exp, err := c.OpenExport(ctx, resourceType, opts, params, exportOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer exp.Close()
+
+	data, err := io.ReadAll(exp)
+	if err != nil {
+		return nil, fmt.Errorf("reading export: %w", err)
+	}
+	return data, nil
+}
+
+// Export is an export file being downloaded. Read it to the end and close it.
+type Export struct {
+	io.Reader
+	body io.Closer
+	// ContentType is the media type the API sent, e.g.
+	// application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.
+	ContentType string
+	// ContentLength is the size of the file in bytes, or -1 when unknown.
+	ContentLength int64
+}
+
+func (e *Export) Close() error {
+	return e.body.Close()
+}
+
+// xlsxSignature is how every .xlsx file (a zip archive) starts.
+var xlsxSignature = []byte("PK\x03\x04")
+
+// OpenExport starts an export like ExportResources and returns the file as a
+// stream. Supports all list filters, type-specific params and
+// export_propertyids for custom category property columns. The response is
+// checked before it is returned: an HTML or JSON page where a file was expected,
+// such as a login page or an error, is reported as an error.
+func (c *Client) OpenExport(ctx context.Context, resourceType string, opts ListOptions, params url.Values, exportOpts ExportOptions) (*Export, error) {
+	q := buildListQuery(opts)
+	for k, v := range params {
+		q[k] = v
+	}
+	format := exportOpts.Format
+	if format == "" {
+		format = "excel"
+	}
+	q.Set("format", format)
+
+	if exportOpts.PropertyIDs != "" {
+		q.Set(propertyIDsParam(resourceType), exportOpts.PropertyIDs)
+	}
+
+	endpoint := "/" + resourceType + "?" + q.Encode()
+	resp, err := c.send(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &Export{
+		body:          resp.Body,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+	}
+	br := bufio.NewReader(resp.Body)
+	exp.Reader = br
+	if err := checkExport(format, exp.ContentType, br); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return exp, nil
+}
+
+// checkExport verifies that a response looks like an export in format.
+func checkExport(format, contentType string, br *bufio.Reader) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/html", "application/json":
+		snippet, _ := br.Peek(200)
+		return fmt.Errorf("expected an export file but got %s: %s", mediaType, strings.Join(strings.Fields(string(snippet)), " "))
+	}
+	if format == "excel" {
+		head, err := br.Peek(len(xlsxSignature))
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("reading export: %w", err)
+		}
+		if !bytes.Equal(head, xlsxSignature) {
+			return fmt.Errorf("expected an Excel file but got %s", orUnknown(mediaType))
+		}
+	}
+	return nil
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown content"
+	}
+	return s
+}
+
+// propertyIDsParam returns the name of the export property IDs parameter.
+// Note: the API uses "export_properyids" (typo in the API) for venues.
+func propertyIDsParam(resourceType string) string {
+	if resourceType == "venues" {
+		return "export_properyids"
+	}
+	return "export_propertyids"
+}
+
+// ExportEvents exports events as an Excel file or, with format "uitkrant", as
+// plain text.
+func (c *Client) ExportEvents(ctx context.Context, opts EventListOptions, exportOpts ExportOptions) ([]byte, error) {
+	return c.ExportResources(ctx, "events", opts.ListOptions, opts.Params(), exportOpts)
+}
+
+// ExportLocations exports locations as an Excel file.
+func (c *Client) ExportLocations(ctx context.Context, opts ListOptions, exportOpts ExportOptions) ([]byte, error) {
+	return c.ExportResources(ctx, "locations", opts, nil, exportOpts)
+}
+
+// ExportVenues exports venues as an Excel file.
+func (c *Client) ExportVenues(ctx context.Context, opts ListOptions, exportOpts ExportOptions) ([]byte, error) {
+	return c.ExportResources(ctx, "venues", opts, nil, exportOpts)
+}
+
+// ExportRoutes exports routes as an Excel file.
+func (c *Client) ExportRoutes(ctx context.Context, opts ListOptions, exportOpts ExportOptions) ([]byte, error) {
+	return c.ExportResources(ctx, "routes", opts, nil, exportOpts)
+}
+
+// ExportEventGroups exports event groups as an Excel file.
+func (c *Client) ExportEventGroups(ctx context.Context, opts ListOptions, exportOpts ExportOptions) ([]byte, error) {
+	return c.ExportResources(ctx, "eventgroups", opts, nil, exportOpts)
+}
+
+// GetResource returns a single resource by type and ID. When fields are given,
+// only those fields are requested and returned; see Project.
+func (c *Client) GetResource(ctx context.Context, resourceType, id string, fields ...string) (json.RawMessage, error) {
+	endpoint := fmt.Sprintf("/%s/%s", resourceType, url.PathEscape(id))
+	if len(fields) > 0 {
+		endpoint += "?" + url.Values{"fields": {strings.Join(fields, ",")}}.Encode()
+	}
+	body, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return Project(body, fields)
+}
+
+// CreateResource creates a resource via POST with the given body and returns the
+// document the API responds with, which carries the new ID.
+func (c *Client) CreateResource(ctx context.Context, resourceType string, data json.RawMessage) (json.RawMessage, error) {
+	body, err := c.doRequest(ctx, "POST", "/"+resourceType, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// UpdateResource updates a resource via PUT with the given body.
+func (c *Client) UpdateResource(ctx context.Context, resourceType, id string, data json.RawMessage) error {
+	endpoint := fmt.Sprintf("/%s/%s", resourceType, url.PathEscape(id))
+	_, err := c.doRequest(ctx, "PUT", endpoint, bytes.NewReader(data))
+	return err
+}
+
+// DeleteResource deletes a resource by type and ID.
+func (c *Client) DeleteResource(ctx context.Context, resourceType, id string) error {
+	endpoint := fmt.Sprintf("/%s/%s", resourceType, url.PathEscape(id))
+	_, err := c.doRequest(ctx, "DELETE", endpoint, nil)
+	return err
+}
+
+// PublishResource sets published=true on a resource.
+func (c *Client) PublishResource(ctx context.Context, resourceType, id string) error {
+	return c.setPublished(ctx, resourceType, id, true)
+}
+
+// UnpublishResource sets published=false on a resource.
+func (c *Client) UnpublishResource(ctx context.Context, resourceType, id string) error {
+	return c.setPublished(ctx, resourceType, id, false)
+}
+
+func (c *Client) setPublished(ctx context.Context, resourceType, id string, published bool) error {
+	return c.ModifyResource(ctx, resourceType, id, func(resource map[string]interface{}) error {
+		resource["published"] = published
+		return nil
+	})
+}
+
+// ModifyResource performs a read-modify-write on a resource: it GETs the current
+// document as a generic map, lets fn change it, and PUTs the result back. Fields
+// fn does not touch are sent back unchanged.
+func (c *Client) ModifyResource(ctx context.Context, resourceType, id string, fn func(resource map[string]interface{}) error) error {
+	// GET current resource
+	body, err := c.GetResource(ctx, resourceType, id)
+	if err != nil {
+		return fmt.Errorf("getting resource: %w", err)
+	}
+
+	// Parse into generic map
+	var resource map[string]interface{}
+	if err := json.Unmarshal(body, &resource); err != nil {
+		return fmt.Errorf("parsing resource: %w", err)
+	}
+
+	if err := fn(resource); err != nil {
+		return err
+	}
+
+	// PUT back
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return fmt.Errorf("marshaling resource: %w", err)
+	}
+
+	return c.UpdateResource(ctx, resourceType, id, data)
+}
+
+// GetComments returns comments for a resource.
+func (c *Client) GetComments(ctx context.Context, resourceType, id string) ([]byte, error) {
+	endpoint := fmt.Sprintf("/%s/%s/comments", resourceType, url.PathEscape(id))
+	return c.doRequest(ctx, "GET", endpoint, nil)
+}
+
+// AddComment adds a comment to a resource.
+func (c *Client) AddComment(ctx context.Context, resourceType, id, message string) error {
+	payload := map[string]string{"text": message}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling comment: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/%s/%s/comments", resourceType, url.PathEscape(id))
+	_, err = c.doRequest(ctx, "POST", endpoint, bytes.NewReader(data))
+	return err
+}
+
+// GetRevisions returns revision history for a resource.
+func (c *Client) GetRevisions(ctx context.Context, resourceType, id string) ([]byte, error) {
+	endpoint := fmt.Sprintf("/%s/%s/revisions", resourceType, url.PathEscape(id))
+	return c.doRequest(ctx, "GET", endpoint, nil)
+}
+
+// GetAccountMe returns info about the current user.
+func (c *Client) GetAccountMe(ctx context.Context) ([]byte, error) {
+	return c.doRequest(ctx, "GET", "/accounts/me", nil)
+}
+
+// ListAccounts returns available accounts.
+func (c *Client) ListAccounts(ctx context.Context) ([]byte, error) {
+	return c.doRequest(ctx, "GET", "/accounts", nil)
+}
+
+// GetAccountData returns the first account object as a generic map.
+// Keywords, markers, ontology and categories are stored on the account.
+func (c *Client) GetAccountData(ctx context.Context) (map[string]interface{}, error) {
+	body, err := c.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing accounts: %w", err)
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("no accounts found")
+	}
+
+	var account map[string]interface{}
+	if err := json.Unmarshal(result.Results[0], &account); err != nil {
+		return nil, fmt.Errorf("parsing account: %w", err)
+	}
+	return account, nil
+}
+
+// GetKeywords returns keywords for a resource type from the account data.
+// The account stores keywords as {type}Keywords (e.g. eventKeywords, locationKeywords).
+func (c *Client) GetKeywords(ctx context.Context, resourceType string) (json.RawMessage, error) {
+	account, err := c.GetAccountData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := resourceType + "Keywords"
+	val, ok := account[key]
+	if !ok {
+		return nil, fmt.Errorf("no keywords field %q found on account", key)
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling keywords: %w", err)
+	}
+	return data, nil
+}
+
+// GetMarkers returns markers for a resource type from the account data.
+// The account stores markers as {type}Markers (e.g. eventMarkers, locationMarkers).
+func (c *Client) GetMarkers(ctx context.Context, resourceType string) (json.RawMessage, error) {
+	account, err := c.GetAccountData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := resourceType + "Markers"
+	val, ok := account[key]
+	if !ok {
+		return nil, fmt.Errorf("no markers field %q found on account", key)
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling markers: %w", err)
+	}
+	return data, nil
+}
+
+// GetOntology returns the categorization ontology from the account data.
+func (c *Client) GetOntology(ctx context.Context) (json.RawMessage, error) {
+	account, err := c.GetAccountData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := account["categorizationOntology"]
+	if !ok {
+		return nil, fmt.Errorf("no categorizationOntology found on account")
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ontology: %w", err)
+	}
+	return data, nil
+}
+
+// ParseResources parses raw JSON results into Resource structs.
+func ParseResources(raw []json.RawMessage) ([]Resource, error) {
+	resources := make([]Resource, 0, len(raw))
+	for _, r := range raw {
+		var res Resource
+		if err := json.Unmarshal(r, &res); err != nil {
+			return nil, fmt.Errorf("parsing resource: %w", err)
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+// ResourceTypeToEndpoint returns the API endpoint for a given resource display type.
+func ResourceTypeToEndpoint(resourceType string) string {
+	switch strings.ToLower(resourceType) {
+	case "event", "events":
+		return "events"
+	case "location", "locations":
+		return "locations"
+	case "route", "routes":
+		return "routes"
+	case "venue", "venues":
+		return "venues"
+	case "eventgroup", "eventgroups":
+		return "eventgroups"
+	default:
+		return resourceType
+	}
+}
+
+// DecodeResults decodes the results of a list response into typed models, e.g.
+// DecodeResults[Event](result).
+func DecodeResults[T any](r *SearchResult) ([]T, error) {
+	items := make([]T, 0, len(r.Results))
+	for _, raw := range r.Results {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("parsing result: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
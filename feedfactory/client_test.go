@@ -0,0 +1,41 @@
+package feedfactory
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("access token sent to another host: %q", auth)
+		}
+		w.Write([]byte(r.Header.Get("User-Agent")))
+	}))
+	defer srv.Close()
+
+	used := false
+	hc := &http.Client{Transport: roundTripper(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	c := New(WithToken("secret"), WithUserAgent("tff-cli/test"), WithHTTPClient(hc))
+	resp, err := c.FetchURL(context.Background(), srv.URL+"/image.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ua, _ := io.ReadAll(resp.Body); string(ua) != "tff-cli/test" {
+		t.Errorf("User-Agent %q", ua)
+	}
+	if !used {
+		t.Error("the HTTP client of WithHTTPClient was not used")
+	}
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,27 @@
+// Package feedfactory is a Go client for the FeedFactory API
+// (https://app.thefeedfactory.nl/api). It is used by the tff command-line tool
+// and can be imported by other services:
+//
+//	client := feedfactory.New(feedfactory.WithToken(os.Getenv("FF_ACCESS_TOKEN")))
+//
+//	result, err := client.ListEvents(ctx, feedfactory.EventListOptions{
+//		ListOptions: feedfactory.ListOptions{Search: "concert", Size: 50},
+//		DateFrom:    "2026-06-01",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	events, err := feedfactory.DecodeResults[feedfactory.Event](result)
+//
+// Every method takes a context, which cancels the request when it is done.
+// Errors reported by the API are returned as *APIError.
+//
+// Documents are available both as raw JSON (GetResource, UpdateResource) and as
+// typed models (GetEvent, UpdateEvent and their counterparts for locations,
+// routes, venues and event groups). The typed models keep fields they don't
+// declare, so a fetched document can be changed and sent back without losing
+// data.
+//
+// The package is versioned with the tff-cli module. Until v1.0.0, a minor
+// release may change the API; such changes are listed in the release notes.
+package feedfactory
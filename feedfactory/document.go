@@ -0,0 +1,255 @@
+package feedfactory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extra holds the fields of a JSON object that its Go type doesn't declare. The
+// typed models keep them so a document can be decoded, changed and sent back
+// with a PUT without losing anything the API added.
+//
+// Extra also remembers the declared fields the document had. Fields that were
+// sent empty (false, "", 0, null, [] or {}) are kept as sent; for the others
+// it holds the empty value of their type. A field that omitempty leaves out is
+// then still sent, so emptying a field, such as removing the last type, clears
+// it on the server instead of keeping the old value.
+type Extra map[string]json.RawMessage
+
+// emptyValues are the encodings of the values omitempty leaves out.
+var emptyValues = map[string]bool{`false`: true, `""`: true, `0`: true, `null`: true, `[]`: true, `{}`: true}
+
+// unmarshalDocument decodes data into v, a pointer to a struct without its own
+// UnmarshalJSON, and stores the fields v doesn't declare in extra.
+func unmarshalDocument(data []byte, v interface{}, extra *Extra) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	*extra = nil
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	known := documentFields(reflect.TypeOf(v).Elem())
+	for k, raw := range fields {
+		if empty, ok := known[strings.ToLower(k)]; ok && !emptyValues[string(bytes.TrimSpace(raw))] {
+			raw = empty
+		}
+		if *extra == nil {
+			*extra = Extra{}
+		}
+		(*extra)[k] = raw
+	}
+	return nil
+}
+
+// marshalDocument encodes v, a struct without its own MarshalJSON, followed by
+// the fields in extra that the encoding of v doesn't have.
+func marshalDocument(v interface{}, extra Extra) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := documentFields(reflect.TypeOf(v))
+	var encoded map[string]bool
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if _, ok := known[strings.ToLower(k)]; ok {
+			// A declared field the document had: send it empty unless it
+			// has a value.
+			if encoded == nil {
+				var fields map[string]json.RawMessage
+				if err := json.Unmarshal(data, &fields); err != nil {
+					return nil, err
+				}
+				encoded = make(map[string]bool, len(fields))
+				for name := range fields {
+					encoded[strings.ToLower(name)] = true
+				}
+			}
+			if encoded[strings.ToLower(k)] {
+				continue
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, k := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, extra[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var documentFieldCache sync.Map // reflect.Type -> map[string]json.RawMessage
+
+// documentFields returns the lower-cased JSON names of the fields of struct type
+// t, including those of embedded structs, with the encoding of their empty
+// value.
+func documentFields(t reflect.Type) map[string]json.RawMessage {
+	if known, ok := documentFieldCache.Load(t); ok {
+		return known.(map[string]json.RawMessage)
+	}
+	known := map[string]json.RawMessage{}
+	addDocumentFields(t, known)
+	documentFieldCache.Store(t, known)
+	return known
+}
+
+func addDocumentFields(t reflect.Type, known map[string]json.RawMessage) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addDocumentFields(f.Type, known)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		known[strings.ToLower(name)] = emptyValue(f.Type)
+	}
+}
+
+// emptyValue returns the JSON encoding of an empty value of type t: [] for
+// lists, {} for maps and the encoding of the zero value otherwise, such as
+// "", false, 0 or null.
+func emptyValue(t reflect.Type) json.RawMessage {
+	switch {
+	case t == reflect.TypeOf(json.RawMessage(nil)):
+		return json.RawMessage("null")
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return json.RawMessage("[]")
+	case t.Kind() == reflect.Map:
+		return json.RawMessage("{}")
+	}
+	data, err := json.Marshal(reflect.Zero(t).Interface())
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// getDocument fetches a resource and decodes it into v.
+func (c *Client) getDocument(ctx context.Context, resourceType, id string, v interface{}) error {
+	data, err := c.GetResource(ctx, resourceType, id)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s %s: %w", strings.TrimSuffix(resourceType, "s"), id, err)
+	}
+	return nil
+}
+
+// putDocument encodes v and sends it as the new version of a resource.
+func (c *Client) putDocument(ctx context.Context, resourceType, id string, v interface{}) error {
+	if id == "" {
+		return fmt.Errorf("cannot update %s without an ID", strings.TrimSuffix(resourceType, "s"))
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", strings.TrimSuffix(resourceType, "s"), err)
+	}
+	return c.UpdateResource(ctx, resourceType, id, data)
+}
+
+// GetEvent fetches an event.
+func (c *Client) GetEvent(ctx context.Context, id string) (*Event, error) {
+	var e Event
+	if err := c.getDocument(ctx, "events", id, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// UpdateEvent replaces an event with e. Fields not declared by Event are sent
+// back as they were fetched.
+func (c *Client) UpdateEvent(ctx context.Context, e *Event) error {
+	return c.putDocument(ctx, "events", e.ID, e)
+}
+
+// GetLocation fetches a location.
+func (c *Client) GetLocation(ctx context.Context, id string) (*Location, error) {
+	var l Location
+	if err := c.getDocument(ctx, "locations", id, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// UpdateLocation replaces a location with l.
+func (c *Client) UpdateLocation(ctx context.Context, l *Location) error {
+	return c.putDocument(ctx, "locations", l.ID, l)
+}
+
+// GetRoute fetches a route.
+func (c *Client) GetRoute(ctx context.Context, id string) (*Route, error) {
+	var r Route
+	if err := c.getDocument(ctx, "routes", id, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// UpdateRoute replaces a route with r.
+func (c *Client) UpdateRoute(ctx context.Context, r *Route) error {
+	return c.putDocument(ctx, "routes", r.ID, r)
+}
+
+// GetVenue fetches a venue.
+func (c *Client) GetVenue(ctx context.Context, id string) (*Venue, error) {
+	var v Venue
+	if err := c.getDocument(ctx, "venues", id, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// UpdateVenue replaces a venue with v.
+func (c *Client) UpdateVenue(ctx context.Context, v *Venue) error {
+	return c.putDocument(ctx, "venues", v.ID, v)
+}
+
+// GetEventGroup fetches an event group.
+func (c *Client) GetEventGroup(ctx context.Context, id string) (*EventGroup, error) {
+	var g EventGroup
+	if err := c.getDocument(ctx, "eventgroups", id, &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// UpdateEventGroup replaces an event group with g.
+func (c *Client) UpdateEventGroup(ctx context.Context, g *EventGroup) error {
+	return c.putDocument(ctx, "eventgroups", g.ID, g)
+}
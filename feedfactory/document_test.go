@@ -0,0 +1,254 @@
+package feedfactory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtures maps the documents in testdata to a constructor of their typed model.
+var fixtures = map[string]func() interface{}{
+	"event.json":      func() interface{} { return new(Event) },
+	"location.json":   func() interface{} { return new(Location) },
+	"route.json":      func() interface{} { return new(Route) },
+	"venue.json":      func() interface{} { return new(Venue) },
+	"eventgroup.json": func() interface{} { return new(EventGroup) },
+	// The events of a group as objects, with items without a string id.
+	"eventgroup-objects.json": func() interface{} { return new(EventGroup) },
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// sameJSON reports whether a and b encode the same value, ignoring key order
+// and whitespace.
+func sameJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("decoding %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// TestRoundTrip decodes every fixture into its typed model and into Resource
+// and checks that encoding gives back the same document: a PUT of an unchanged
+// document must not drop or alter anything.
+func TestRoundTrip(t *testing.T) {
+	for name, typed := range fixtures {
+		for _, v := range []interface{}{typed(), new(Resource)} {
+			t.Run(name+"/"+reflect.TypeOf(v).Elem().Name(), func(t *testing.T) {
+				want := readFixture(t, name)
+				if err := json.Unmarshal(want, v); err != nil {
+					t.Fatal(err)
+				}
+				got, err := json.Marshal(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !sameJSON(t, got, want) {
+					t.Errorf("round trip changed the document:\n got %s\nwant %s", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeFixture(t *testing.T) {
+	var e Event
+	if err := json.Unmarshal(readFixture(t, "event.json"), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.GetTitle() != "Zomerconcert in het Vondelpark" || e.Location.City() != "Amsterdam" {
+		t.Errorf("title %q, city %q", e.GetTitle(), e.Location.City())
+	}
+	if got := e.GetMarkers(); !reflect.DeepEqual(got, []string{"featured", "zomer"}) {
+		t.Errorf("markers %q", got)
+	}
+	if kws := e.GetKeywords(); len(kws) != 1 || kws[0].ID != "kw-42" || kws[0].Label != "muziek" {
+		t.Errorf("keywords %+v", kws)
+	}
+	if e.ContactInfo.GetPhone() != "+31 20 123 4567" || e.ContactInfo.GetEmail() != "info@example.nl" {
+		t.Errorf("phone %q, email %q", e.ContactInfo.GetPhone(), e.ContactInfo.GetEmail())
+	}
+	if e.LastUpdated.IsZero() || e.Created.IsZero() {
+		t.Errorf("timestamps not parsed: %v, %v", e.LastUpdated, e.Created)
+	}
+	if n := len(e.Calendar.SingleDates); n != 2 || e.Calendar.SingleDates[1].StartTime != "20:00:00" {
+		t.Errorf("single dates %+v", e.Calendar.SingleDates)
+	}
+	if p := e.PriceElements; len(p) != 2 || p[0].Free || !p[1].Free ||
+		len(p[0].PriceValues) != 1 || p[0].PriceValues[0].From != 12.5 || p[0].PriceValues[0].Until != 25 ||
+		p[0].Description == nil || p[0].Description.Label != "Entree" ||
+		len(p[1].Comments) != 1 || p[1].Comments[0].Text != "Kinderen tot 12 jaar" {
+		t.Errorf("price elements %+v", p)
+	}
+
+	var l Location
+	if err := json.Unmarshal(readFixture(t, "location.json"), &l); err != nil {
+		t.Fatal(err)
+	}
+	if p := l.Calendar.PatternDates; len(p) != 1 || p[0].RecurrencyType != "weekly" || p[0].Recurrency != 1 || len(p[0].OpeningTimes) != 2 {
+		t.Errorf("pattern dates %+v", p)
+	} else if o := p[0].OpeningTimes[0]; o.Weekday != "6" || len(o.Whens) != 1 ||
+		o.Whens[0].TimeStart != "10:00" || o.Whens[0].TimeEnd != "18:00" || o.Whens[0].Status != "open" {
+		t.Errorf("opening times %+v", o)
+	}
+	if l.Keywords != nil {
+		t.Errorf("keywords %+v, want none for null", l.Keywords)
+	}
+	if l.ContactInfo.GetPhone() != "020-5550000" {
+		t.Errorf("phone %q", l.ContactInfo.GetPhone())
+	}
+}
+
+// TestEmptiedFieldsAreSent checks that clearing a field the document had sends
+// it empty, so the server clears it too, while fields the document didn't have
+// stay out.
+func TestEmptiedFieldsAreSent(t *testing.T) {
+	var e Event
+	if err := json.Unmarshal(readFixture(t, "event.json"), &e); err != nil {
+		t.Fatal(err)
+	}
+	e.Categories.Types = nil
+	e.Markers.Values = nil
+	e.Media = nil
+	e.ContactInfo.URLs = nil
+	e.Calendar.SingleDates[0].EndTime = ""
+	e.Location.Address = nil
+	e.Performers = []Performer{}
+	e.ExternalID = ""
+
+	data, err := json.Marshal(&e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	categories := doc["trcItemCategories"].(map[string]interface{})
+	contact := doc["contactinfo"].(map[string]interface{})
+	date := doc["calendar"].(map[string]interface{})["singleDates"].([]interface{})[0].(map[string]interface{})
+	location := doc["location"].(map[string]interface{})
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"trcItemCategories.types", categories["types"], []interface{}{}},
+		{"markers", doc["markers"], ""},
+		{"media", doc["media"], []interface{}{}},
+		{"contactinfo.urls", contact["urls"], []interface{}{}},
+		{"singleDates[0].endtime", date["endtime"], ""},
+		{"location.address", location["address"], nil},
+		{"performers", doc["performers"], []interface{}{}},
+		{"externalid", doc["externalid"], ""},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %#v, want %#v", tt.name, tt.got, tt.want)
+		}
+	}
+	if _, ok := location["address"]; !ok {
+		t.Error("location.address was left out")
+	}
+	for _, key := range []string{"physical", "events"} {
+		if _, ok := doc[key]; ok {
+			t.Errorf("%s was sent although the document didn't have it", key)
+		}
+	}
+	if _, ok := contact["faxes"]; ok {
+		t.Error("contactinfo.faxes was sent although the document didn't have it")
+	}
+}
+
+// TestSparseDocuments checks that timestamps and descriptions a document
+// doesn't have stay out, and that those it has are kept as they were.
+func TestSparseDocuments(t *testing.T) {
+	docs := []string{
+		`{"id":"E1","published":true,"wfstatus":"approved","trcItemDetails":[{"lang":"nl","title":"Concert"}]}`,
+		`{"id":"E1","published":false,"wfstatus":"draft","trcItemDetails":[{"lang":"nl","title":"Concert","shortdescription":""}],"creationdate":null}`,
+		`{"id":"E1","published":false,"wfstatus":"draft","lastupdated":"gisteren","creationdate":1767225600000}`,
+	}
+	for _, doc := range docs {
+		for _, v := range []interface{}{new(Event), new(Resource)} {
+			if err := json.Unmarshal([]byte(doc), v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameJSON(t, got, []byte(doc)) {
+				t.Errorf("%T: got %s\nwant %s", v, got, doc)
+			}
+		}
+	}
+}
+
+func TestMemberList(t *testing.T) {
+	var g EventGroup
+	if err := json.Unmarshal(readFixture(t, "eventgroup-objects.json"), &g); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"5b3e4c9a-2d3f-6a7c-1e4b-0f9a8c7d6e5f", "6c4f5d0b-3e4a-7b8d-2f5c-1a0b9d8e7f6a"}
+	if !reflect.DeepEqual(g.Events.IDs, want) {
+		t.Fatalf("IDs %q, want %q", g.Events.IDs, want)
+	}
+
+	// Remove the first event and add one: the other items stay as they were.
+	g.Events.IDs = []string{want[1], "new-event"}
+	got, err := json.Marshal(g.Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `[{"id":"6c4f5d0b-3e4a-7b8d-2f5c-1a0b9d8e7f6a","order":2},{"trcid":"LEGACY-991","title":"Slotavond"},{"id":42},null,{"id":"new-event"}]`
+	if !sameJSON(t, got, []byte(wantJSON)) {
+		t.Errorf("got %s\nwant %s", got, wantJSON)
+	}
+
+	tests := []struct {
+		doc  string
+		ids  []string
+		edit []string
+		want string
+	}{
+		{`["a","b","a"]`, []string{"a", "b", "a"}, nil, `["a","b","a"]`},
+		{`["a","b"]`, []string{"a", "b"}, []string{"b", "c"}, `["b","c"]`},
+		{`["a",7]`, []string{"a"}, []string{}, `[7]`},
+		{`null`, nil, nil, `null`},
+		{`{"ids":["a"]}`, nil, nil, `{"ids":["a"]}`},
+		{`[]`, []string{}, []string{"a"}, `["a"]`},
+	}
+	for _, tt := range tests {
+		var m MemberList
+		if err := json.Unmarshal([]byte(tt.doc), &m); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m.IDs, tt.ids) {
+			t.Errorf("%s: IDs %q, want %q", tt.doc, m.IDs, tt.ids)
+		}
+		if tt.edit != nil {
+			m.IDs = tt.edit
+		}
+		got, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s edited to %q: got %s, want %s", tt.doc, tt.edit, got, tt.want)
+		}
+	}
+}
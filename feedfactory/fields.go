@@ -0,0 +1,148 @@
+package feedfactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldTree is a parsed field selection. A node without children keeps the
+// whole value.
+type fieldTree map[string]fieldTree
+
+// newFieldTree parses dotted field paths. A path selects the whole value, so it
+// wins over longer paths below it: location,location.address.city keeps all of
+// location.
+func newFieldTree(fields []string) fieldTree {
+	paths := make([][]string, 0, len(fields))
+	for _, f := range fields {
+		var path []string
+		for _, part := range strings.Split(strings.TrimSpace(f), ".") {
+			if part != "" {
+				path = append(path, strings.ToLower(part))
+			}
+		}
+		if len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	// Shorter paths first: an existing node without children is then always a
+	// selected field rather than one still to get children.
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+
+	tree := fieldTree{}
+	for _, path := range paths {
+		node := tree
+		for _, key := range path {
+			child, ok := node[key]
+			if ok && len(child) == 0 {
+				break
+			}
+			if !ok {
+				child = fieldTree{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// Project reduces a JSON document to the given fields. Fields are dotted paths
+// such as location.address.city; a path through an array applies to every
+// element. Field names are matched case-insensitively, unknown fields are
+// ignored and the top-level id is always kept.
+func Project(doc json.RawMessage, fields []string) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return doc, nil
+	}
+	tree := newFieldTree(fields)
+	if _, ok := tree["id"]; !ok {
+		tree["id"] = fieldTree{}
+	}
+	return tree.project(doc)
+}
+
+func (t fieldTree) project(raw json.RawMessage) (json.RawMessage, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(t) == 0 || len(raw) == 0 {
+		return raw, nil
+	}
+
+	switch raw[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			p, err := t.project(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = p
+		}
+		return json.Marshal(items)
+
+	case '{':
+		// Decode key by key so the kept fields stay in the API's order.
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		first := true
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", tok)
+			}
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			sub, keep := t[strings.ToLower(key)]
+			if !keep {
+				continue
+			}
+			if value, err = sub.project(value); err != nil {
+				return nil, err
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			k, _ := json.Marshal(key)
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	}
+
+	// A scalar where an object was expected: nothing to select from.
+	return raw, nil
+}
+
+// Project reduces every result to the given fields; see Project.
+func (r *SearchResult) Project(fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	for i, raw := range r.Results {
+		p, err := Project(raw, fields)
+		if err != nil {
+			return fmt.Errorf("selecting fields: %w", err)
+		}
+		r.Results[i] = p
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package feedfactory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProject(t *testing.T) {
+	doc := `{"id":"E1","wfstatus":"approved","location":{"label":"Paradiso","address":{"city":"Amsterdam","street":"Weteringschans"}},"trcItemDetails":[{"lang":"nl","title":"Concert","shortdescription":"Live"},{"lang":"en","title":"Gig"}]}`
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{nil, doc},
+		{[]string{"wfstatus"}, `{"id":"E1","wfstatus":"approved"}`},
+		{[]string{"location.address.city"}, `{"id":"E1","location":{"address":{"city":"Amsterdam"}}}`},
+		{[]string{"trcItemDetails.lang", "TRCITEMDETAILS.Title"}, `{"id":"E1","trcItemDetails":[{"lang":"nl","title":"Concert"},{"lang":"en","title":"Gig"}]}`},
+		// A parent field wins over its children, in either order.
+		{[]string{"location", "location.address.city"}, `{"id":"E1","location":{"label":"Paradiso","address":{"city":"Amsterdam","street":"Weteringschans"}}}`},
+		{[]string{"location.address.city", "location"}, `{"id":"E1","location":{"label":"Paradiso","address":{"city":"Amsterdam","street":"Weteringschans"}}}`},
+		{[]string{"location.address", "location.address.city"}, `{"id":"E1","location":{"address":{"city":"Amsterdam","street":"Weteringschans"}}}`},
+		{[]string{"wfstatus.value", "unknown", " . "}, `{"id":"E1","wfstatus":"approved"}`},
+	}
+	for _, tt := range tests {
+		got, err := Project([]byte(doc), tt.fields)
+		if err != nil {
+			t.Errorf("%q: %v", tt.fields, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", strings.Join(tt.fields, ","), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,906 @@
+package feedfactory
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Common holds the fields every resource document has, whatever its type. The
+// typed models (Event, Location, Route, Venue, EventGroup) and the catch-all
+// Resource embed it.
+type Common struct {
+	ID             string          `json:"id"`
+	Slug           string          `json:"slug,omitempty"`
+	Types          []string        `json:"types,omitempty"`
+	Published      bool            `json:"published"`
+	Offline        bool            `json:"offline,omitempty"`
+	Deleted        bool            `json:"deleted,omitempty"`
+	WFStatus       string          `json:"wfstatus"`
+	LastUpdated    Timestamp       `json:"lastupdated,omitzero"`
+	LastUpdatedBy  string          `json:"lastupdatedby,omitempty"`
+	Created        Timestamp       `json:"creationdate,omitzero"`
+	Owner          string          `json:"owner,omitempty"`
+	ContactInfo    *ContactInfo    `json:"contactinfo,omitempty"`
+	Media          []Media         `json:"media,omitempty"`
+	URLs           []URLEntry      `json:"urls,omitempty"`
+	Files          []File          `json:"files,omitempty"`
+	Markers        MarkerList      `json:"markers,omitzero"`
+	Keywords       []Keyword       `json:"keywords,omitempty"`
+	UserOrg        string          `json:"userorganisation,omitempty"`
+	TRCID          string          `json:"trcid,omitempty"`
+	ExternalID     string          `json:"externalid,omitempty"`
+	EntityType     string          `json:"entitytype,omitempty"`
+	TRCItemDetails []TRCItemDetail `json:"trcItemDetails,omitempty"`
+	Categories     *ItemCategories `json:"trcItemCategories,omitempty"`
+	Translations   *Translations   `json:"translations,omitempty"`
+}
+
+// Resource represents a generic FeedFactory resource (event, location, route, venue, eventgroup).
+// The API returns titles and descriptions inside trcItemDetails, grouped by language.
+// It has the type-specific fields of all resource types; use the typed models
+// when the type is known.
+type Resource struct {
+	Common
+	Calendar      *Calendar      `json:"calendar,omitempty"`
+	Location      *LocationInfo  `json:"location,omitempty"`
+	Physical      *Physical      `json:"physical,omitempty"`
+	Performers    []Performer    `json:"performers,omitempty"`
+	PriceElements []PriceElement `json:"priceElements,omitempty"`
+	Events        MemberList     `json:"events,omitzero"`
+	Extra         Extra          `json:"-"`
+}
+
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	type plain Resource
+	return unmarshalDocument(data, (*plain)(r), &r.Extra)
+}
+
+func (r Resource) MarshalJSON() ([]byte, error) {
+	type plain Resource
+	return marshalDocument(plain(r), r.Extra)
+}
+
+// Event is an event document.
+type Event struct {
+	Common
+	Calendar      *Calendar      `json:"calendar,omitempty"`
+	Location      *LocationInfo  `json:"location,omitempty"`
+	Performers    []Performer    `json:"performers,omitempty"`
+	PriceElements []PriceElement `json:"priceElements,omitempty"`
+	Extra         Extra          `json:"-"`
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type plain Event
+	return unmarshalDocument(data, (*plain)(e), &e.Extra)
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	return marshalDocument(plain(e), e.Extra)
+}
+
+// Location is a location document: a place such as a museum, restaurant or
+// park. Not to be confused with LocationInfo, the address block inside a
+// document.
+type Location struct {
+	Common
+	Location *LocationInfo `json:"location,omitempty"`
+	Calendar *Calendar     `json:"calendar,omitempty"`
+	Extra    Extra         `json:"-"`
+}
+
+func (l *Location) UnmarshalJSON(data []byte) error {
+	type plain Location
+	return unmarshalDocument(data, (*plain)(l), &l.Extra)
+}
+
+func (l Location) MarshalJSON() ([]byte, error) {
+	type plain Location
+	return marshalDocument(plain(l), l.Extra)
+}
+
+// Route is a route document (a walking, cycling or other tour).
+type Route struct {
+	Common
+	Location *LocationInfo `json:"location,omitempty"`
+	Physical *Physical     `json:"physical,omitempty"`
+	Extra    Extra         `json:"-"`
+}
+
+func (r *Route) UnmarshalJSON(data []byte) error {
+	type plain Route
+	return unmarshalDocument(data, (*plain)(r), &r.Extra)
+}
+
+func (r Route) MarshalJSON() ([]byte, error) {
+	type plain Route
+	return marshalDocument(plain(r), r.Extra)
+}
+
+// Venue is a venue document: a place that hosts events.
+type Venue struct {
+	Common
+	Location *LocationInfo `json:"location,omitempty"`
+	Extra    Extra         `json:"-"`
+}
+
+func (v *Venue) UnmarshalJSON(data []byte) error {
+	type plain Venue
+	return unmarshalDocument(data, (*plain)(v), &v.Extra)
+}
+
+func (v Venue) MarshalJSON() ([]byte, error) {
+	type plain Venue
+	return marshalDocument(plain(v), v.Extra)
+}
+
+// EventGroup is an event group document, such as a festival or a series.
+type EventGroup struct {
+	Common
+	Calendar *Calendar     `json:"calendar,omitempty"`
+	Location *LocationInfo `json:"location,omitempty"`
+	Events   MemberList    `json:"events,omitzero"`
+	Extra    Extra         `json:"-"`
+}
+
+func (g *EventGroup) UnmarshalJSON(data []byte) error {
+	type plain EventGroup
+	return unmarshalDocument(data, (*plain)(g), &g.Extra)
+}
+
+func (g EventGroup) MarshalJSON() ([]byte, error) {
+	type plain EventGroup
+	return marshalDocument(plain(g), g.Extra)
+}
+
+// MarkerList handles the markers field, which the API returns either as an
+// array of strings or as a comma-separated string. The original representation
+// is kept when the list is encoded again.
+type MarkerList struct {
+	Values   []string
+	asString bool
+}
+
+func (m *MarkerList) UnmarshalJSON(data []byte) error {
+	// Try as array first
+	var arr []string
+	if err := json.Unmarshal(data, &arr); err == nil {
+		*m = MarkerList{Values: arr}
+		return nil
+	}
+	// Try as single string (possibly comma-separated)
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = MarkerList{asString: true}
+		if s != "" {
+			m.Values = strings.Split(s, ",")
+		}
+		return nil
+	}
+	// Null or unparseable
+	*m = MarkerList{}
+	return nil
+}
+
+func (m MarkerList) MarshalJSON() ([]byte, error) {
+	if m.asString {
+		return json.Marshal(strings.Join(m.Values, ","))
+	}
+	if m.Values == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(m.Values)
+}
+
+// IsZero reports whether the document had no markers field, so omitzero leaves
+// it out. An empty list that was sent is encoded again.
+func (m MarkerList) IsZero() bool {
+	return m.Values == nil && !m.asString
+}
+
+// MemberList handles the events field of an event group. The API
+// documentation doesn't describe the field, so its schema is a guess: it is
+// read as a list of event IDs, given as strings or as objects with an id.
+// Items that don't fit, and a value that is not a list, are kept and written
+// back as they were.
+//
+// Items are written back in the order they were read, leaving out the IDs
+// that were removed from IDs; IDs added since follow at the end, as objects
+// {"id": ...} when the list had objects.
+type MemberList struct {
+	IDs   []string
+	items []memberItem
+	// raw is a value that is not a list, such as null.
+	raw       json.RawMessage
+	asObjects bool
+}
+
+// memberItem is an item of the list as it was read: an event ID with the item
+// it came from, or an item without one.
+type memberItem struct {
+	id  string
+	raw json.RawMessage
+}
+
+func (m *MemberList) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil || items == nil {
+		*m = MemberList{raw: append(json.RawMessage(nil), data...)}
+		return nil
+	}
+	*m = MemberList{IDs: []string{}}
+	for _, item := range items {
+		var id string
+		var obj struct {
+			ID string `json:"id"`
+		}
+		switch {
+		case json.Unmarshal(item, &id) == nil && id != "":
+		case json.Unmarshal(item, &obj) == nil && obj.ID != "":
+			id = obj.ID
+			m.asObjects = true
+		default:
+			m.items = append(m.items, memberItem{raw: item})
+			continue
+		}
+		m.IDs = append(m.IDs, id)
+		m.items = append(m.items, memberItem{id: id, raw: item})
+	}
+	return nil
+}
+
+func (m MemberList) MarshalJSON() ([]byte, error) {
+	if m.IDs == nil && m.raw != nil {
+		return m.raw, nil
+	}
+	// left counts the IDs that still have to be written.
+	left := map[string]int{}
+	for _, id := range m.IDs {
+		left[id]++
+	}
+	items := []json.RawMessage{}
+	for _, item := range m.items {
+		if item.id != "" {
+			if left[item.id] == 0 {
+				continue
+			}
+			left[item.id]--
+		}
+		items = append(items, item.raw)
+	}
+	for _, id := range m.IDs {
+		if left[id] == 0 {
+			continue
+		}
+		left[id]--
+		var v interface{} = id
+		if m.asObjects {
+			v = map[string]string{"id": id}
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, raw)
+	}
+	return json.Marshal(items)
+}
+
+// IsZero reports whether the document had no events field, so omitzero leaves
+// it out.
+func (m MemberList) IsZero() bool {
+	return m.IDs == nil && m.raw == nil
+}
+
+// GetMarkers returns the markers as a string slice.
+func (r *Common) GetMarkers() []string {
+	return r.Markers.Values
+}
+
+// GetKeywords returns the keywords. A document sent with "keywords": null has
+// none.
+func (r *Common) GetKeywords() []Keyword {
+	return r.Keywords
+}
+
+// TRCItemDetail holds language-specific content for a resource.
+type TRCItemDetail struct {
+	Lang             string `json:"lang"`
+	Title            string `json:"title"`
+	ShortDescription string `json:"shortdescription,omitempty"`
+	LongDescription  string `json:"longdescription,omitempty"`
+	Extra            Extra  `json:"-"`
+}
+
+func (d *TRCItemDetail) UnmarshalJSON(data []byte) error {
+	type plain TRCItemDetail
+	return unmarshalDocument(data, (*plain)(d), &d.Extra)
+}
+
+func (d TRCItemDetail) MarshalJSON() ([]byte, error) {
+	type plain TRCItemDetail
+	return marshalDocument(plain(d), d.Extra)
+}
+
+// Detail returns the language-specific content for lang, or nil if there is none.
+func (r *Common) Detail(lang string) *TRCItemDetail {
+	for i := range r.TRCItemDetails {
+		if r.TRCItemDetails[i].Lang == lang {
+			return &r.TRCItemDetails[i]
+		}
+	}
+	return nil
+}
+
+// ItemCategories holds the ontology categories assigned to a resource: its types and
+// its category properties (categories that may carry a value).
+type ItemCategories struct {
+	Types      []CategoryType     `json:"types,omitempty"`
+	Categories []CategoryProperty `json:"categories,omitempty"`
+	Extra      Extra              `json:"-"`
+}
+
+func (c *ItemCategories) UnmarshalJSON(data []byte) error {
+	type plain ItemCategories
+	return unmarshalDocument(data, (*plain)(c), &c.Extra)
+}
+
+func (c ItemCategories) MarshalJSON() ([]byte, error) {
+	type plain ItemCategories
+	return marshalDocument(plain(c), c.Extra)
+}
+
+type CategoryType struct {
+	CatID     string `json:"catid"`
+	IsDefault bool   `json:"isDefault,omitempty"`
+	Extra     Extra  `json:"-"`
+}
+
+func (c *CategoryType) UnmarshalJSON(data []byte) error {
+	type plain CategoryType
+	return unmarshalDocument(data, (*plain)(c), &c.Extra)
+}
+
+func (c CategoryType) MarshalJSON() ([]byte, error) {
+	type plain CategoryType
+	return marshalDocument(plain(c), c.Extra)
+}
+
+type CategoryProperty struct {
+	CatID    string `json:"catid"`
+	Value    string `json:"value,omitempty"`
+	ValueID  string `json:"valueid,omitempty"`
+	Datatype string `json:"datatype,omitempty"`
+	Extra    Extra  `json:"-"`
+}
+
+func (c *CategoryProperty) UnmarshalJSON(data []byte) error {
+	type plain CategoryProperty
+	return unmarshalDocument(data, (*plain)(c), &c.Extra)
+}
+
+func (c CategoryProperty) MarshalJSON() ([]byte, error) {
+	type plain CategoryProperty
+	return marshalDocument(plain(c), c.Extra)
+}
+
+// CategoryIDs returns the IDs of all categories assigned to the resource, including
+// the plain types list.
+func (r *Common) CategoryIDs() []string {
+	ids := append([]string(nil), r.Types...)
+	if r.Categories != nil {
+		for _, t := range r.Categories.Types {
+			ids = append(ids, t.CatID)
+		}
+		for _, c := range r.Categories.Categories {
+			ids = append(ids, c.CatID)
+		}
+	}
+	return ids
+}
+
+type Translations struct {
+	AvailableLanguages []string `json:"availableLanguages,omitempty"`
+	PrimaryLanguage    string   `json:"primaryLanguage,omitempty"`
+	Extra              Extra    `json:"-"`
+}
+
+func (t *Translations) UnmarshalJSON(data []byte) error {
+	type plain Translations
+	return unmarshalDocument(data, (*plain)(t), &t.Extra)
+}
+
+func (t Translations) MarshalJSON() ([]byte, error) {
+	type plain Translations
+	return marshalDocument(plain(t), t.Extra)
+}
+
+// GetTitle returns the best available title, preferring nl > en > de > first available.
+func (r *Common) GetTitle() string {
+	if len(r.TRCItemDetails) == 0 {
+		return "-"
+	}
+	// Try preferred languages in order
+	for _, lang := range []string{"nl", "en", "de"} {
+		for _, d := range r.TRCItemDetails {
+			if d.Lang == lang && d.Title != "" {
+				return d.Title
+			}
+		}
+	}
+	// Fallback to first available
+	if r.TRCItemDetails[0].Title != "" {
+		return r.TRCItemDetails[0].Title
+	}
+	return "-"
+}
+
+// GetShortDescription returns the best available short description.
+func (r *Common) GetShortDescription() string {
+	if len(r.TRCItemDetails) == 0 {
+		return ""
+	}
+	for _, lang := range []string{"nl", "en", "de"} {
+		for _, d := range r.TRCItemDetails {
+			if d.Lang == lang && d.ShortDescription != "" {
+				return d.ShortDescription
+			}
+		}
+	}
+	return r.TRCItemDetails[0].ShortDescription
+}
+
+// GetCity returns the city from the location address, if available.
+func (r *Resource) GetCity() string {
+	return r.Location.City()
+}
+
+// GetFirstDate returns the first single date, if available.
+func (r *Resource) GetFirstDate() string {
+	return r.Calendar.FirstDate()
+}
+
+type Calendar struct {
+	CalendarType string        `json:"calendarType,omitempty"`
+	SingleDates  []SingleDate  `json:"singleDates,omitempty"`
+	PatternDates []PatternDate `json:"patternDates,omitempty"`
+	Cancelled    bool          `json:"cancelled,omitempty"`
+	SoldOut      bool          `json:"soldout,omitempty"`
+	Extra        Extra         `json:"-"`
+}
+
+func (c *Calendar) UnmarshalJSON(data []byte) error {
+	type plain Calendar
+	return unmarshalDocument(data, (*plain)(c), &c.Extra)
+}
+
+func (c Calendar) MarshalJSON() ([]byte, error) {
+	type plain Calendar
+	return marshalDocument(plain(c), c.Extra)
+}
+
+// FirstDate returns the first single date, if available. It is safe to call on
+// a nil calendar.
+func (c *Calendar) FirstDate() string {
+	if c != nil && len(c.SingleDates) > 0 {
+		return c.SingleDates[0].Date
+	}
+	return ""
+}
+
+type SingleDate struct {
+	Date      string `json:"date,omitempty"`
+	StartTime string `json:"starttime,omitempty"`
+	EndTime   string `json:"endtime,omitempty"`
+	// Cancelled and SoldOut mark a single date, like the flags of the same
+	// name on Calendar mark the whole event. Unlike those they are not in the
+	// API documentation, so a server that doesn't know them may drop them on
+	// save; 'events dates cancel' and 'soldout' read the event back to check.
+	Cancelled bool  `json:"cancelled,omitempty"`
+	SoldOut   bool  `json:"soldout,omitempty"`
+	Extra     Extra `json:"-"`
+}
+
+func (d *SingleDate) UnmarshalJSON(data []byte) error {
+	type plain SingleDate
+	return unmarshalDocument(data, (*plain)(d), &d.Extra)
+}
+
+func (d SingleDate) MarshalJSON() ([]byte, error) {
+	type plain SingleDate
+	return marshalDocument(plain(d), d.Extra)
+}
+
+// PatternDate is a recurring period in a calendar, such as the opening hours of
+// a location in summer. RecurrencyType is how it repeats, e.g. weekly, and
+// Recurrency every how many of those; OpeningTimes gives the hours per weekday.
+type PatternDate struct {
+	StartDate      string        `json:"startdate,omitempty"`
+	EndDate        string        `json:"enddate,omitempty"`
+	RecurrencyType string        `json:"recurrencyType,omitempty"`
+	Recurrency     int           `json:"recurrency,omitempty"`
+	Occurrence     int           `json:"occurrence,omitempty"`
+	OpeningTimes   []OpeningTime `json:"openingTimes,omitempty"`
+	Extra          Extra         `json:"-"`
+}
+
+func (d *PatternDate) UnmarshalJSON(data []byte) error {
+	type plain PatternDate
+	return unmarshalDocument(data, (*plain)(d), &d.Extra)
+}
+
+func (d PatternDate) MarshalJSON() ([]byte, error) {
+	type plain PatternDate
+	return marshalDocument(plain(d), d.Extra)
+}
+
+// OpeningTime holds the hours of a pattern date on one weekday, given by its
+// number as a string.
+type OpeningTime struct {
+	Weekday string `json:"weekday,omitempty"`
+	Whens   []When `json:"whens,omitempty"`
+	Extra   Extra  `json:"-"`
+}
+
+func (o *OpeningTime) UnmarshalJSON(data []byte) error {
+	type plain OpeningTime
+	return unmarshalDocument(data, (*plain)(o), &o.Extra)
+}
+
+func (o OpeningTime) MarshalJSON() ([]byte, error) {
+	type plain OpeningTime
+	return marshalDocument(plain(o), o.Extra)
+}
+
+// When is a period of a day, with a status such as open.
+type When struct {
+	TimeStart string `json:"timestart,omitempty"`
+	TimeEnd   string `json:"timeend,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Extra     Extra  `json:"-"`
+}
+
+func (w *When) UnmarshalJSON(data []byte) error {
+	type plain When
+	return unmarshalDocument(data, (*plain)(w), &w.Extra)
+}
+
+func (w When) MarshalJSON() ([]byte, error) {
+	type plain When
+	return marshalDocument(plain(w), w.Extra)
+}
+
+// LocationInfo is the location block of a document: where an event takes place,
+// or where a location, venue or route is.
+type LocationInfo struct {
+	Address *Address `json:"address,omitempty"`
+	Label   string   `json:"label,omitempty"`
+	Extra   Extra    `json:"-"`
+}
+
+func (l *LocationInfo) UnmarshalJSON(data []byte) error {
+	type plain LocationInfo
+	return unmarshalDocument(data, (*plain)(l), &l.Extra)
+}
+
+func (l LocationInfo) MarshalJSON() ([]byte, error) {
+	type plain LocationInfo
+	return marshalDocument(plain(l), l.Extra)
+}
+
+// City returns the city from the address, if available. It is safe to call on
+// a nil location.
+func (l *LocationInfo) City() string {
+	if l != nil && l.Address != nil {
+		return l.Address.City
+	}
+	return ""
+}
+
+type Address struct {
+	Street    string  `json:"street,omitempty"`
+	HouseNr   string  `json:"housenr,omitempty"`
+	ZipCode   string  `json:"zipcode,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Extra     Extra   `json:"-"`
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	type plain Address
+	return unmarshalDocument(data, (*plain)(a), &a.Extra)
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	type plain Address
+	return marshalDocument(plain(a), a.Extra)
+}
+
+type Physical struct {
+	Distance  string `json:"distance,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+	RouteType string `json:"routetype,omitempty"`
+	Extra     Extra  `json:"-"`
+}
+
+func (p *Physical) UnmarshalJSON(data []byte) error {
+	type plain Physical
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p Physical) MarshalJSON() ([]byte, error) {
+	type plain Physical
+	return marshalDocument(plain(p), p.Extra)
+}
+
+// ContactInfo uses flexible types since the API returns both simple and complex contact structures.
+type ContactInfo struct {
+	// Simple fields (may be string or object)
+	Phone json.RawMessage `json:"phone,omitempty"`
+	Mail  json.RawMessage `json:"mail,omitempty"`
+
+	// Array fields
+	Phones    []ContactPhone `json:"phones,omitempty"`
+	Mails     []ContactMail  `json:"mails,omitempty"`
+	Faxes     []ContactPhone `json:"faxes,omitempty"`
+	URLs      []ContactURL   `json:"urls,omitempty"`
+	Addresses []Address      `json:"addresses,omitempty"`
+	Extra     Extra          `json:"-"`
+}
+
+func (ci *ContactInfo) UnmarshalJSON(data []byte) error {
+	type plain ContactInfo
+	return unmarshalDocument(data, (*plain)(ci), &ci.Extra)
+}
+
+func (ci ContactInfo) MarshalJSON() ([]byte, error) {
+	type plain ContactInfo
+	return marshalDocument(plain(ci), ci.Extra)
+}
+
+// ContactPhone is a phone or fax number.
+type ContactPhone struct {
+	Number string `json:"number"`
+	Extra  Extra  `json:"-"`
+}
+
+func (p *ContactPhone) UnmarshalJSON(data []byte) error {
+	type plain ContactPhone
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p ContactPhone) MarshalJSON() ([]byte, error) {
+	type plain ContactPhone
+	return marshalDocument(plain(p), p.Extra)
+}
+
+type ContactMail struct {
+	Email string `json:"email"`
+	Extra Extra  `json:"-"`
+}
+
+func (m *ContactMail) UnmarshalJSON(data []byte) error {
+	type plain ContactMail
+	return unmarshalDocument(data, (*plain)(m), &m.Extra)
+}
+
+func (m ContactMail) MarshalJSON() ([]byte, error) {
+	type plain ContactMail
+	return marshalDocument(plain(m), m.Extra)
+}
+
+type ContactURL struct {
+	URL            string `json:"url"`
+	TargetLanguage string `json:"targetLanguage,omitempty"`
+	URLServiceType string `json:"urlServiceType,omitempty"`
+	Extra          Extra  `json:"-"`
+}
+
+func (u *ContactURL) UnmarshalJSON(data []byte) error {
+	type plain ContactURL
+	return unmarshalDocument(data, (*plain)(u), &u.Extra)
+}
+
+func (u ContactURL) MarshalJSON() ([]byte, error) {
+	type plain ContactURL
+	return marshalDocument(plain(u), u.Extra)
+}
+
+// GetPhone returns the primary phone number.
+func (ci *ContactInfo) GetPhone() string {
+	// Try phones array first
+	if len(ci.Phones) > 0 && ci.Phones[0].Number != "" {
+		return ci.Phones[0].Number
+	}
+	// Try phone object
+	if ci.Phone != nil {
+		var p ContactPhone
+		if json.Unmarshal(ci.Phone, &p) == nil && p.Number != "" {
+			return p.Number
+		}
+		// Try as plain string
+		var s string
+		if json.Unmarshal(ci.Phone, &s) == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// GetEmail returns the primary email.
+func (ci *ContactInfo) GetEmail() string {
+	if len(ci.Mails) > 0 && ci.Mails[0].Email != "" {
+		return ci.Mails[0].Email
+	}
+	if ci.Mail != nil {
+		var m ContactMail
+		if json.Unmarshal(ci.Mail, &m) == nil && m.Email != "" {
+			return m.Email
+		}
+		var s string
+		if json.Unmarshal(ci.Mail, &s) == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+type Media struct {
+	URL       string `json:"url,omitempty"`
+	Main      bool   `json:"main,omitempty"`
+	MediaType string `json:"mediatype,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Extra     Extra  `json:"-"`
+}
+
+func (m *Media) UnmarshalJSON(data []byte) error {
+	type plain Media
+	return unmarshalDocument(data, (*plain)(m), &m.Extra)
+}
+
+func (m Media) MarshalJSON() ([]byte, error) {
+	type plain Media
+	return marshalDocument(plain(m), m.Extra)
+}
+
+type URLEntry struct {
+	URL     string `json:"url,omitempty"`
+	URLType string `json:"urltype,omitempty"`
+	Label   string `json:"label,omitempty"`
+	Extra   Extra  `json:"-"`
+}
+
+func (u *URLEntry) UnmarshalJSON(data []byte) error {
+	type plain URLEntry
+	return unmarshalDocument(data, (*plain)(u), &u.Extra)
+}
+
+func (u URLEntry) MarshalJSON() ([]byte, error) {
+	type plain URLEntry
+	return marshalDocument(plain(u), u.Extra)
+}
+
+// File is a downloadable file attached to a resource, such as a brochure.
+type File struct {
+	URL      string `json:"url,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Extra    Extra  `json:"-"`
+}
+
+func (f *File) UnmarshalJSON(data []byte) error {
+	type plain File
+	return unmarshalDocument(data, (*plain)(f), &f.Extra)
+}
+
+func (f File) MarshalJSON() ([]byte, error) {
+	type plain File
+	return marshalDocument(plain(f), f.Extra)
+}
+
+// Performer is an artist or company appearing at an event.
+type Performer struct {
+	Label string `json:"label,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Extra Extra  `json:"-"`
+}
+
+func (p *Performer) UnmarshalJSON(data []byte) error {
+	type plain Performer
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p Performer) MarshalJSON() ([]byte, error) {
+	type plain Performer
+	return marshalDocument(plain(p), p.Extra)
+}
+
+// PriceElement is one price of an event: free, or one or more amounts, with a
+// description and comments per language.
+type PriceElement struct {
+	Free        bool              `json:"free,omitempty"`
+	PriceValues []PriceValue      `json:"priceValues,omitempty"`
+	Description *PriceDescription `json:"description,omitempty"`
+	Comments    []PriceComment    `json:"comments,omitempty"`
+	Extra       Extra             `json:"-"`
+}
+
+func (p *PriceElement) UnmarshalJSON(data []byte) error {
+	type plain PriceElement
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p PriceElement) MarshalJSON() ([]byte, error) {
+	type plain PriceElement
+	return marshalDocument(plain(p), p.Extra)
+}
+
+// PriceValue is an amount in euros, or a range of amounts from From until
+// Until.
+type PriceValue struct {
+	From  float64 `json:"from,omitempty"`
+	Until float64 `json:"until,omitempty"`
+	Extra Extra   `json:"-"`
+}
+
+func (p *PriceValue) UnmarshalJSON(data []byte) error {
+	type plain PriceValue
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p PriceValue) MarshalJSON() ([]byte, error) {
+	type plain PriceValue
+	return marshalDocument(plain(p), p.Extra)
+}
+
+// PriceDescription names a price, such as Entree.
+type PriceDescription struct {
+	Label string `json:"label,omitempty"`
+	Extra Extra  `json:"-"`
+}
+
+func (p *PriceDescription) UnmarshalJSON(data []byte) error {
+	type plain PriceDescription
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p PriceDescription) MarshalJSON() ([]byte, error) {
+	type plain PriceDescription
+	return marshalDocument(plain(p), p.Extra)
+}
+
+// PriceComment is a remark on a price in one language.
+type PriceComment struct {
+	Lang  string `json:"lang,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Extra Extra  `json:"-"`
+}
+
+func (p *PriceComment) UnmarshalJSON(data []byte) error {
+	type plain PriceComment
+	return unmarshalDocument(data, (*plain)(p), &p.Extra)
+}
+
+func (p PriceComment) MarshalJSON() ([]byte, error) {
+	type plain PriceComment
+	return marshalDocument(plain(p), p.Extra)
+}
+
+type Keyword struct {
+	ID    string `json:"id,omitempty"`
+	Label string `json:"label,omitempty"`
+	Value string `json:"value,omitempty"`
+	Extra Extra  `json:"-"`
+}
+
+func (k *Keyword) UnmarshalJSON(data []byte) error {
+	type plain Keyword
+	return unmarshalDocument(data, (*plain)(k), &k.Extra)
+}
+
+func (k Keyword) MarshalJSON() ([]byte, error) {
+	type plain Keyword
+	return marshalDocument(plain(k), k.Extra)
+}
@@ -0,0 +1,67 @@
+package feedfactory
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Option configures a Client; see New.
+type Option func(*Client)
+
+// TokenSource supplies the access token sent with each request. Implementations
+// can refresh or rotate tokens; they must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// WithBaseURL sets the API endpoint, e.g. for a test server. A trailing slash is
+// ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithToken authenticates requests with a fixed access token.
+func WithToken(token string) Option {
+	return WithTokenSource(StaticToken(token))
+}
+
+// WithTokenSource authenticates requests with tokens from ts.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = ts
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests, for custom transports,
+// proxies or timeouts.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithLogger logs each request (method, path, status and duration) at debug
+// level to logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
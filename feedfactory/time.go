@@ -0,0 +1,109 @@
+package feedfactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Europe/Amsterdam must resolve on machines without a zoneinfo database.
+)
+
+// APILocation is the time zone FeedFactory uses for event dates and times and for
+// timestamps sent without an offset (Dutch local time).
+var APILocation = mustLoadLocation("Europe/Amsterdam")
+
+// timestampLayouts are the formats the API has been seen to use for creation and
+// modification times. Layouts without an offset are read as Dutch local time.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Timestamp is a point in time returned by the API. It accepts ISO 8601 strings
+// (with or without an offset) as well as epoch milliseconds, and marshals back to
+// the exact value it was read from so round trips don't alter documents. A value
+// in an unknown format leaves Time zero but is kept for display.
+type Timestamp struct {
+	time.Time
+	raw  json.RawMessage
+	text string
+}
+
+// ParseTimestamp parses an API timestamp string.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, APILocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	*t = Timestamp{}
+	if len(data) == 0 || string(data) == "null" || string(data) == `""` {
+		return nil
+	}
+
+	var s string
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(data)
+	}
+	t.raw = append(json.RawMessage(nil), data...)
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		// Don't fail a whole document over a date format we don't know.
+		t.text = s
+		return nil
+	}
+	t.Time = parsed
+	return nil
+}
+
+// IsZero reports whether the timestamp is unset. A value in an unknown format
+// is set, although Time is zero, so omitzero keeps it in documents.
+func (t Timestamp) IsZero() bool {
+	return t.Time.IsZero() && t.raw == nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.raw != nil {
+		return t.raw, nil
+	}
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time.Format(time.RFC3339))
+}
+
+// Display formats the timestamp for tables in the given zone, or returns "" when
+// it is unset.
+func (t Timestamp) Display(loc *time.Location) string {
+	if t.Time.IsZero() {
+		return t.text
+	}
+	return t.Time.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
@@ -6,3 +6,21 @@ require (
 	github.com/alecthomas/kong v1.14.0
 	github.com/joho/godotenv v1.5.1
 )
+
+require (
+	github.com/itchyny/gojq v0.12.19
+	github.com/xuri/excelize/v2 v2.11.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/itchyny/timefmt-go v0.1.8 // indirect
+	github.com/richardlehane/mscfb v1.0.7 // indirect
+	github.com/richardlehane/msoleps v1.0.6 // indirect
+	github.com/tiendc/go-deepcopy v1.7.2 // indirect
+	github.com/xuri/efp v0.0.1 // indirect
+	github.com/xuri/nfp v0.0.2-0.20250530014748-2ddeb826f9a9 // indirect
+	golang.org/x/crypto v0.53.0 // indirect
+	golang.org/x/net v0.56.0 // indirect
+	golang.org/x/text v0.38.0 // indirect
+)
@@ -10,6 +10,8 @@ import (
 
 type Config struct {
 	Token string
+	// TimeZone is the IANA zone timestamps are displayed in (FF_TIMEZONE).
+	TimeZone string
 }
 
 func ConfigLocations() []string {
@@ -44,7 +46,7 @@ func Load(configFile string) (*Config, error) {
 		return nil, fmt.Errorf("FF_ACCESS_TOKEN not set.\n\n%s", configHelp())
 	}
 
-	return &Config{Token: token}, nil
+	return &Config{Token: token, TimeZone: os.Getenv("FF_TIMEZONE")}, nil
 }
 
 func configHelp() string {
@@ -85,6 +87,12 @@ Config file locations (first found wins):
   - .env (current directory)
   - ~/.config/tff-cli/.env
 
+Optional settings:
+  FF_TIMEZONE           Time zone for displaying timestamps (default
+                        Europe/Amsterdam; e.g. UTC or Local). The --tz flag
+                        overrides it. Date filters always use Dutch time.
+
 Example .env file:
-  FF_ACCESS_TOKEN=your-access-token-here`)
+  FF_ACCESS_TOKEN=your-access-token-here
+  FF_TIMEZONE=Europe/Amsterdam`)
 }
@@ -0,0 +1,466 @@
+// Package dedupe finds likely duplicate resources by comparing normalized titles,
+// addresses, coordinates and (for events) dates.
+package dedupe
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// Options tune the matching.
+type Options struct {
+	// Threshold is the minimum pair score (0-1) for two resources to be grouped.
+	Threshold float64
+	// Events enables event matching: duplicates must share a date (or both have
+	// no single dates) and a location.
+	Events bool
+}
+
+// Member is a resource in a duplicate group.
+type Member struct {
+	ID           string                `json:"id"`
+	Title        string                `json:"title"`
+	Owner        string                `json:"owner,omitempty"`
+	UserOrg      string                `json:"userorganisation,omitempty"`
+	Address      string                `json:"address,omitempty"`
+	WFStatus     string                `json:"wfstatus"`
+	Published    bool                  `json:"published"`
+	Created      feedfactory.Timestamp `json:"created"`
+	Score        float64               `json:"score"`
+	completeness int
+}
+
+// Group is a cluster of resources that are likely the same thing.
+type Group struct {
+	// Confidence is the weakest pair score that links the group together.
+	Confidence float64  `json:"confidence"`
+	Members    []Member `json:"members"`
+}
+
+// candidate is a resource with its normalized comparison fields precomputed.
+type candidate struct {
+	res      *feedfactory.Resource
+	title    string
+	tokens   map[string]bool
+	zip      string
+	city     string
+	street   string
+	houseNr  string
+	label    string
+	lat, lon float64
+	dates    map[string]bool
+}
+
+// Find returns duplicate groups among resources, most confident first.
+func Find(resources []feedfactory.Resource, opts Options) []Group {
+	cands := make([]*candidate, len(resources))
+	for i := range resources {
+		cands[i] = newCandidate(&resources[i])
+	}
+
+	parent := make([]int, len(cands))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	// Best pair score per member; a group's confidence is the weakest of these.
+	memberScore := make([]float64, len(cands))
+	for _, p := range candidatePairs(cands, opts.Events) {
+		a, b := cands[p[0]], cands[p[1]]
+		var score float64
+		if opts.Events {
+			score = eventScore(a, b)
+		} else {
+			score = placeScore(a, b)
+		}
+		if score < opts.Threshold {
+			continue
+		}
+		memberScore[p[0]] = math.Max(memberScore[p[0]], score)
+		memberScore[p[1]] = math.Max(memberScore[p[1]], score)
+		ra, rb := find(p[0]), find(p[1])
+		if ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	clusters := map[int][]int{}
+	for i := range cands {
+		clusters[find(i)] = append(clusters[find(i)], i)
+	}
+
+	var groups []Group
+	for _, idx := range clusters {
+		if len(idx) < 2 {
+			continue
+		}
+		conf := 1.0
+		var members []Member
+		for _, i := range idx {
+			conf = math.Min(conf, memberScore[i])
+			members = append(members, newMember(cands[i], memberScore[i]))
+		}
+		sort.SliceStable(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+		groups = append(groups, Group{Confidence: round2(conf), Members: members})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Confidence != groups[j].Confidence {
+			return groups[i].Confidence > groups[j].Confidence
+		}
+		return groups[i].Members[0].ID < groups[j].Members[0].ID
+	})
+	return groups
+}
+
+func newCandidate(r *feedfactory.Resource) *candidate {
+	c := &candidate{res: r, title: normalize(r.GetTitle())}
+	c.tokens = tokenSet(c.title)
+	if r.Location != nil {
+		c.label = normalize(r.Location.Label)
+		if a := r.Location.Address; a != nil {
+			c.zip = strings.ToUpper(strings.ReplaceAll(a.ZipCode, " ", ""))
+			c.city = normalize(a.City)
+			c.street = normalize(a.Street)
+			c.houseNr = strings.ToLower(strings.ReplaceAll(a.HouseNr, " ", ""))
+			c.lat, c.lon = a.Latitude, a.Longitude
+		}
+	}
+	if r.Calendar != nil {
+		c.dates = map[string]bool{}
+		for _, d := range r.Calendar.SingleDates {
+			if len(d.Date) >= 10 {
+				c.dates[d.Date[:10]] = true
+			}
+		}
+	}
+	return c
+}
+
+// candidatePairs returns the index pairs worth scoring. Resources are only compared
+// when they share a blocking key (postcode area, city, coordinate cell or, for
+// events, a date or, without dates, a title or place), which keeps large sets from being compared all-to-all.
+func candidatePairs(cands []*candidate, events bool) [][2]int {
+	blocks := map[string][]int{}
+	for i, c := range cands {
+		for _, key := range blockKeys(c, events) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	seen := map[[2]int]bool{}
+	var pairs [][2]int
+	for _, idx := range blocks {
+		for x := 0; x < len(idx); x++ {
+			for y := x + 1; y < len(idx); y++ {
+				p := [2]int{idx[x], idx[y]}
+				if p[0] > p[1] {
+					p[0], p[1] = p[1], p[0]
+				}
+				if p[0] == p[1] || seen[p] {
+					continue
+				}
+				seen[p] = true
+				pairs = append(pairs, p)
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
+
+func blockKeys(c *candidate, events bool) []string {
+	if events {
+		keys := make([]string, 0, len(c.dates))
+		for d := range c.dates {
+			keys = append(keys, "date:"+d)
+		}
+		if len(keys) == 0 {
+			// Pattern or always-open events have no single dates: let undated
+			// events meet on their title or their place.
+			keys = append(keys, "undated-title:"+c.title)
+			if place := c.place(); place != "" {
+				keys = append(keys, "undated-place:"+place)
+			}
+		}
+		return keys
+	}
+
+	var keys []string
+	if len(c.zip) >= 4 {
+		keys = append(keys, "zip:"+c.zip[:4])
+	}
+	if c.city != "" {
+		keys = append(keys, "city:"+c.city)
+	}
+	if c.lat != 0 || c.lon != 0 {
+		// ~1km cells; include the neighbours so points near a cell edge still meet.
+		cy, cx := int(math.Floor(c.lat*100)), int(math.Floor(c.lon*100))
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				keys = append(keys, fmt.Sprintf("geo:%d:%d", cy+dy, cx+dx))
+			}
+		}
+	}
+	if len(keys) == 0 {
+		// Nothing to locate it by: fall back to the title so identical names still meet.
+		keys = append(keys, "title:"+c.title)
+	}
+	return keys
+}
+
+// place is the location label, postcode or city of a candidate, whichever is
+// known first.
+func (c *candidate) place() string {
+	switch {
+	case c.label != "":
+		return c.label
+	case c.zip != "":
+		return c.zip
+	default:
+		return c.city
+	}
+}
+
+// placeScore scores two locations or venues: mostly title, backed by address or
+// coordinates. Different titles never match on address alone, since one building
+// often houses several venues.
+func placeScore(a, b *candidate) float64 {
+	title := titleSimilarity(a, b)
+	if title < 0.5 {
+		return 0
+	}
+	place := math.Max(addressSimilarity(a, b), geoSimilarity(a, b))
+	return round2(0.6*title + 0.4*place)
+}
+
+// eventScore scores two events. They must share a date, or both have no single
+// dates, and be at the same place.
+func eventScore(a, b *candidate) float64 {
+	shared := len(a.dates) == 0 && len(b.dates) == 0
+	for d := range a.dates {
+		if b.dates[d] {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		return 0
+	}
+
+	place := math.Max(addressSimilarity(a, b), geoSimilarity(a, b))
+	if a.label != "" && a.label == b.label {
+		place = 1
+	}
+	if place < 0.5 {
+		return 0
+	}
+
+	title := titleSimilarity(a, b)
+	if title < 0.5 {
+		return 0
+	}
+	return round2(0.7*title + 0.3*place)
+}
+
+// titleSimilarity is the better of token overlap and edit-distance similarity, so
+// both reordered words ("Theater De Kom" / "De Kom Theater") and typos match.
+func titleSimilarity(a, b *candidate) float64 {
+	if a.title == "" || b.title == "" {
+		return 0
+	}
+	if a.title == b.title {
+		return 1
+	}
+	return math.Max(jaccard(a.tokens, b.tokens), levenshteinRatio(a.title, b.title))
+}
+
+func addressSimilarity(a, b *candidate) float64 {
+	if a.zip != "" && a.zip == b.zip {
+		if a.houseNr != "" && a.houseNr == b.houseNr {
+			return 1
+		}
+		return 0.8
+	}
+	if a.street != "" && a.street == b.street && a.city == b.city {
+		if a.houseNr != "" && a.houseNr == b.houseNr {
+			return 1
+		}
+		return 0.6
+	}
+	if a.city != "" && a.city == b.city {
+		return 0.3
+	}
+	return 0
+}
+
+func geoSimilarity(a, b *candidate) float64 {
+	if (a.lat == 0 && a.lon == 0) || (b.lat == 0 && b.lon == 0) {
+		return 0
+	}
+	d := Distance(a.lat, a.lon, b.lat, b.lon)
+	switch {
+	case d <= 50:
+		return 1
+	case d <= 250:
+		return 0.7
+	case d <= 1000:
+		return 0.3
+	}
+	return 0
+}
+
+// Distance returns the great-circle distance in meters between two coordinates.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+var diacritics = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a", "å", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ç", "c", "ñ", "n", "ß", "ss",
+)
+
+// stopwords are articles and filler words ignored when comparing titles.
+var stopwords = map[string]bool{
+	"de": true, "het": true, "een": true, "en": true, "van": true, "in": true,
+	"the": true, "a": true, "an": true, "and": true, "of": true,
+	"der": true, "die": true, "das": true, "und": true,
+}
+
+// normalize lowercases s, strips diacritics and punctuation and collapses spaces.
+func normalize(s string) string {
+	s = diacritics.Replace(strings.ToLower(s))
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space && b.Len() > 0 {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func tokenSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range strings.Fields(s) {
+		if !stopwords[t] {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for t := range a {
+		if b[t] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	dist := prev[len(rb)]
+	return 1 - float64(dist)/float64(max(len(ra), len(rb)))
+}
+
+func newMember(c *candidate, score float64) Member {
+	r := c.res
+	var addr []string
+	if r.Location != nil && r.Location.Address != nil {
+		a := r.Location.Address
+		street := strings.TrimSpace(a.Street + " " + a.HouseNr)
+		for _, part := range []string{street, strings.TrimSpace(a.ZipCode + " " + a.City)} {
+			if part != "" {
+				addr = append(addr, part)
+			}
+		}
+	}
+	return Member{
+		ID:           r.ID,
+		Title:        r.GetTitle(),
+		Owner:        r.Owner,
+		UserOrg:      r.UserOrg,
+		Address:      strings.Join(addr, ", "),
+		WFStatus:     r.WFStatus,
+		Published:    r.Published,
+		Created:      r.Created,
+		Score:        round2(score),
+		completeness: completeness(r),
+	}
+}
+
+// completeness counts filled-in content, used to pick which duplicate to keep.
+func completeness(r *feedfactory.Resource) int {
+	n := len(r.TRCItemDetails) + len(r.Media) + len(r.URLs)
+	if r.GetShortDescription() != "" {
+		n++
+	}
+	if r.Location != nil && r.Location.Address != nil && r.Location.Address.Latitude != 0 {
+		n++
+	}
+	if r.ContactInfo != nil {
+		n++
+	}
+	return n
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
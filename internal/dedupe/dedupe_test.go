@@ -0,0 +1,208 @@
+package dedupe
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// resource decodes a document written as JSON.
+func resource(t *testing.T, doc string) feedfactory.Resource {
+	t.Helper()
+	var r feedfactory.Resource
+	if err := json.Unmarshal([]byte(doc), &r); err != nil {
+		t.Fatalf("%s: %v", doc, err)
+	}
+	return r
+}
+
+// place returns a location document with a title, an address and coordinates.
+func place(id, title, street, nr, zip, city string, lat, lon float64) string {
+	doc, _ := json.Marshal(map[string]interface{}{
+		"id":             id,
+		"trcItemDetails": []map[string]string{{"lang": "nl", "title": title}},
+		"location": map[string]interface{}{"address": map[string]interface{}{
+			"street": street, "housenr": nr, "zipcode": zip, "city": city, "latitude": lat, "longitude": lon,
+		}},
+	})
+	return string(doc)
+}
+
+// event returns an event document with a title, a location label and dates.
+func event(id, title, label string, dates ...string) string {
+	doc := map[string]interface{}{
+		"id":             id,
+		"trcItemDetails": []map[string]string{{"lang": "nl", "title": title}},
+		"location":       map[string]string{"label": label},
+	}
+	if len(dates) > 0 {
+		var single []map[string]string
+		for _, d := range dates {
+			single = append(single, map[string]string{"date": d})
+		}
+		doc["calendar"] = map[string]interface{}{"singleDates": single}
+	}
+	data, _ := json.Marshal(doc)
+	return string(data)
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Theater De Kom", "theater de kom"},
+		{"  Café 't Hoekje!! ", "cafe t hoekje"},
+		{"Jazz-in-de-Tuin (2026)", "jazz in de tuin 2026"},
+		{"Straße / Ñandú", "strasse nandu"},
+		{"...", ""},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.in); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{52.37, 4.89, 52.37, 4.89, 0},
+		// A thousandth of a degree of latitude is about 111 m.
+		{52.370, 4.89, 52.371, 4.89, 111},
+		// Amsterdam Centraal to Utrecht Centraal.
+		{52.3791, 4.9003, 52.0894, 5.1100, 35161},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.lat1, tt.lon1, tt.lat2, tt.lon2); math.Abs(got-tt.want) > tt.want/100+1 {
+			t.Errorf("Distance(%v, %v, %v, %v) = %.0f, want about %.0f", tt.lat1, tt.lon1, tt.lat2, tt.lon2, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceScore(t *testing.T) {
+	kom := place("A", "Theater De Kom", "Stationsweg", "6", "3432 AA", "Nieuwegein", 52.0290, 5.0800)
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"reordered title, same address", kom,
+			place("B", "De Kom Theater", "Stationsweg", "6", "3432AA", "Nieuwegein", 0, 0), 1},
+		{"typo in title, same postcode, other number", kom,
+			place("B", "Theatre De Kom", "Stationsweg", "8", "3432 AA", "Nieuwegein", 0, 0), 0.83},
+		{"same title, coordinates 24 m apart", place("A", "Café 't Hoekje", "", "", "", "", 52.3600, 4.8800),
+			place("B", "Cafe t Hoekje", "", "", "", "", 52.3602, 4.8801), 1},
+		{"same title, same street, no number", place("A", "Bibliotheek", "Oudegracht", "", "", "Utrecht", 0, 0),
+			place("B", "Bibliotheek", "Oudegracht", "", "", "Utrecht", 0, 0), 0.84},
+		// Near misses.
+		{"other venue in the same building", place("A", "Paradiso", "Weteringschans", "6", "1017 SG", "Amsterdam", 52.3622, 4.8838),
+			place("B", "Melkweg", "Weteringschans", "6", "1017 SG", "Amsterdam", 52.3622, 4.8838), 0},
+		{"same title, a kilometre apart", place("A", "Bibliotheek Centrum", "", "", "", "", 52.3700, 4.8900),
+			place("B", "Bibliotheek Centrum", "", "", "", "", 52.3790, 4.8900), 0.6},
+		{"same title, only the city in common", place("A", "Stadhuis", "Markt", "1", "", "Delft", 0, 0),
+			place("B", "Stadhuis", "Coolsingel", "40", "", "Delft", 0, 0), 0.72},
+	}
+	for _, tt := range tests {
+		a, b := resource(t, tt.a), resource(t, tt.b)
+		if got := placeScore(newCandidate(&a), newCandidate(&b)); got != tt.want {
+			t.Errorf("%s: score %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventScore(t *testing.T) {
+	jazz := event("A", "Jazz in de tuin", "Vondelpark", "2026-06-01", "2026-06-08")
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"same title, place and a date", jazz, event("B", "Jazz in de Tuin!", "vondelpark", "2026-06-08T00:00:00"), 1},
+		{"both undated at the same place", event("A", "Rondleiding", "Rijksmuseum"), event("B", "Rondleiding", "Rijksmuseum"), 1},
+		// Near misses.
+		{"no shared date", jazz, event("B", "Jazz in de tuin", "Vondelpark", "2026-06-15"), 0},
+		{"dated and undated", jazz, event("B", "Jazz in de tuin", "Vondelpark"), 0},
+		{"same date, other place", jazz, event("B", "Jazz in de tuin", "Paradiso", "2026-06-01"), 0},
+		{"same date and place, other event", jazz, event("B", "Kindervoorstelling", "Vondelpark", "2026-06-01"), 0},
+	}
+	for _, tt := range tests {
+		a, b := resource(t, tt.a), resource(t, tt.b)
+		if got := eventScore(newCandidate(&a), newCandidate(&b)); got != tt.want {
+			t.Errorf("%s: score %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// groupIDs returns the member IDs of each group.
+func groupIDs(groups []Group) [][]string {
+	out := [][]string{}
+	for _, g := range groups {
+		var ids []string
+		for _, m := range g.Members {
+			ids = append(ids, m.ID)
+		}
+		out = append(out, ids)
+	}
+	return out
+}
+
+func TestFindPlaces(t *testing.T) {
+	docs := []string{
+		place("L1", "Theater De Kom", "Stationsweg", "6", "3432 AA", "Nieuwegein", 0, 0),
+		place("L2", "De Kom Theater", "Stationsweg", "6", "3432AA", "Nieuwegein", 0, 0),
+		place("L3", "Theatre De Kom", "Stationsweg", "8", "3432 AA", "Nieuwegein", 0, 0),
+		place("L4", "Paradiso", "Weteringschans", "6", "1017 SG", "Amsterdam", 52.3622, 4.8838),
+		place("L5", "Melkweg", "Weteringschans", "6", "1017 SG", "Amsterdam", 52.3622, 4.8838),
+		place("L6", "Bibliotheek Centrum", "", "", "", "", 52.3700, 4.8900),
+		place("L7", "Bibliotheek Centrum", "", "", "", "", 52.3790, 4.8900),
+		place("L8", "Café 't Hoekje", "", "", "", "", 52.3600, 4.8800),
+		place("L9", "Cafe t Hoekje", "", "", "", "", 52.3602, 4.8801),
+		// Same name in another town: not even compared.
+		place("L10", "Theater De Kom", "Markt", "1", "7511 GB", "Enschede", 52.2215, 6.8937),
+	}
+	var resources []feedfactory.Resource
+	for _, d := range docs {
+		resources = append(resources, resource(t, d))
+	}
+
+	groups := Find(resources, Options{Threshold: 0.7})
+	want := [][]string{{"L8", "L9"}, {"L1", "L2", "L3"}}
+	if got := groupIDs(groups); !reflect.DeepEqual(got, want) {
+		t.Fatalf("groups %q, want %q", got, want)
+	}
+	// L3 only matches at 0.83, so that is what holds the group together.
+	if groups[0].Confidence != 1 || groups[1].Confidence != 0.83 {
+		t.Errorf("confidences %v and %v, want 1 and 0.83", groups[0].Confidence, groups[1].Confidence)
+	}
+
+	// A stricter threshold drops the typo and so the weakest link.
+	if got := groupIDs(Find(resources, Options{Threshold: 0.9})); !reflect.DeepEqual(got, [][]string{{"L1", "L2"}, {"L8", "L9"}}) {
+		t.Errorf("groups at 0.9: %q", got)
+	}
+}
+
+func TestFindEvents(t *testing.T) {
+	docs := []string{
+		event("E1", "Jazz in de tuin", "Vondelpark", "2026-06-01", "2026-06-08"),
+		event("E2", "Jazz in de Tuin!", "Vondelpark", "2026-06-08"),
+		event("E3", "Jazz in de tuin", "Vondelpark", "2026-06-15"),
+		event("E4", "Jazz in de tuin", "Paradiso", "2026-06-01"),
+		event("E5", "Kindervoorstelling", "Vondelpark", "2026-06-01"),
+		event("E6", "Rondleiding", "Rijksmuseum"),
+		event("E7", "Rondleiding", "Rijksmuseum"),
+		event("E8", "Rondleiding", "Stedelijk"),
+		event("E9", "Jazz in de tuin", "Vondelpark"),
+	}
+	var resources []feedfactory.Resource
+	for _, d := range docs {
+		resources = append(resources, resource(t, d))
+	}
+	got := groupIDs(Find(resources, Options{Threshold: 0.7, Events: true}))
+	want := [][]string{{"E1", "E2"}, {"E6", "E7"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package dedupe
+
+import "sort"
+
+// Plan proposes, for every duplicate group, which resource to keep and which to
+// merge into it. Applying the plan is left to the editor or a later tool.
+type Plan struct {
+	Type   string      `json:"type"`
+	Groups []PlanGroup `json:"groups"`
+}
+
+type PlanGroup struct {
+	Confidence float64  `json:"confidence"`
+	Keep       string   `json:"keep"`
+	Merge      []string `json:"merge"`
+	Reason     string   `json:"reason"`
+	Members    []Member `json:"members"`
+}
+
+// NewPlan picks a survivor for each group: published and approved resources first,
+// then the most complete one, then the oldest.
+func NewPlan(resourceType string, groups []Group) *Plan {
+	plan := &Plan{Type: resourceType, Groups: []PlanGroup{}}
+	for _, g := range groups {
+		ranked := append([]Member(nil), g.Members...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			a, b := ranked[i], ranked[j]
+			if a.Published != b.Published {
+				return a.Published
+			}
+			if (a.WFStatus == "approved") != (b.WFStatus == "approved") {
+				return a.WFStatus == "approved"
+			}
+			if a.completeness != b.completeness {
+				return a.completeness > b.completeness
+			}
+			return !a.Created.Time.IsZero() && (b.Created.Time.IsZero() || a.Created.Before(b.Created.Time))
+		})
+
+		keep := ranked[0]
+		reason := "oldest"
+		switch {
+		case keep.Published && !ranked[1].Published:
+			reason = "published"
+		case keep.WFStatus == "approved" && ranked[1].WFStatus != "approved":
+			reason = "approved"
+		case keep.completeness > ranked[1].completeness:
+			reason = "most complete"
+		}
+
+		pg := PlanGroup{Confidence: g.Confidence, Keep: keep.ID, Reason: reason, Members: g.Members}
+		for _, m := range ranked[1:] {
+			pg.Merge = append(pg.Merge, m.ID)
+		}
+		plan.Groups = append(plan.Groups, pg)
+	}
+	return plan
+}
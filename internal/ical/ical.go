@@ -0,0 +1,422 @@
+// Package ical reads events from iCalendar (RFC 5545) data and expands their
+// recurrence rules into occurrences.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT with its recurrence exceptions. Times are in the zone of
+// their TZID, in UTC, or in the default zone for floating times.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	// Status is TENTATIVE, CONFIRMED or CANCELLED, if set.
+	Status     string
+	Categories []string
+
+	Start time.Time
+	End   time.Time
+	// AllDay events have dates without a time of day.
+	AllDay bool
+
+	// RRule is the recurrence rule, if any, as in the file.
+	RRule   string
+	RDates  []time.Time
+	ExDates []time.Time
+	// Overrides are the changed or cancelled instances of a recurring event,
+	// keyed by RECURRENCE-ID.
+	Overrides []*Override
+}
+
+// Override is a VEVENT that replaces one instance of a recurring event.
+type Override struct {
+	RecurrenceID time.Time
+	Start        time.Time
+	End          time.Time
+	Status       string
+}
+
+// Occurrence is one instance of an event.
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Parse reads the VEVENTs of an iCalendar stream. Times without a zone, and
+// with a TZID that is not a known IANA zone, are read in loc. Instances that
+// override part of a recurring event are attached to it.
+func Parse(r io.Reader, loc *time.Location) ([]*Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	var overrides []*Override
+	var overrideUIDs []string
+	var cur *Event
+	var curRecurrence *time.Time
+	var curLine int
+	depth := 0 // nesting inside the current VEVENT (VALARM etc.)
+
+	for _, l := range lines {
+		name, params, value, ok := parseLine(l.text)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && cur == nil:
+			cur, curRecurrence, curLine, depth = &Event{}, nil, l.num, 0
+			continue
+		case name == "BEGIN" && cur != nil:
+			depth++
+			continue
+		case name == "END" && cur != nil && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT") && cur != nil:
+			if cur.UID == "" {
+				return nil, fmt.Errorf("line %d: event without UID", curLine)
+			}
+			if cur.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %s has no DTSTART", curLine, cur.UID)
+			}
+			if cur.End.IsZero() {
+				cur.End = cur.Start
+				if cur.AllDay {
+					cur.End = cur.Start.AddDate(0, 0, 1)
+				}
+			}
+			if curRecurrence != nil {
+				overrides = append(overrides, &Override{RecurrenceID: *curRecurrence, Start: cur.Start, End: cur.End, Status: cur.Status})
+				overrideUIDs = append(overrideUIDs, cur.UID)
+			} else {
+				events = append(events, cur)
+			}
+			cur = nil
+			continue
+		}
+		if cur == nil || depth > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			cur.UID = value
+		case "SUMMARY":
+			cur.Summary = unescape(value)
+		case "DESCRIPTION":
+			cur.Description = unescape(value)
+		case "LOCATION":
+			cur.Location = unescape(value)
+		case "URL":
+			cur.URL = value
+		case "STATUS":
+			cur.Status = strings.ToUpper(value)
+		case "CATEGORIES":
+			for _, c := range splitEscaped(value) {
+				if c = strings.TrimSpace(unescape(c)); c != "" {
+					cur.Categories = append(cur.Categories, c)
+				}
+			}
+		case "DTSTART":
+			t, allDay, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTSTART: %w", l.num, err)
+			}
+			cur.Start, cur.AllDay = t, allDay
+		case "DTEND":
+			t, _, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTEND: %w", l.num, err)
+			}
+			cur.End = t
+		case "DURATION":
+			d, err := parseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DURATION: %w", l.num, err)
+			}
+			// DTSTART comes first in practice; DTEND wins if both are given.
+			if cur.End.IsZero() && !cur.Start.IsZero() {
+				cur.End = cur.Start.Add(d)
+			}
+		case "RRULE":
+			cur.RRule = value
+		case "RDATE", "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, _, err := parseTime(v, params, loc)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %s: %w", l.num, name, err)
+				}
+				if name == "RDATE" {
+					cur.RDates = append(cur.RDates, t)
+				} else {
+					cur.ExDates = append(cur.ExDates, t)
+				}
+			}
+		case "RECURRENCE-ID":
+			t, _, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: RECURRENCE-ID: %w", l.num, err)
+			}
+			curRecurrence = &t
+		}
+	}
+
+	byUID := map[string]*Event{}
+	for _, e := range events {
+		byUID[e.UID] = e
+	}
+	for i, o := range overrides {
+		if e := byUID[overrideUIDs[i]]; e != nil {
+			e.Overrides = append(e.Overrides, o)
+		}
+	}
+	return events, nil
+}
+
+// Occurrences returns the instances of e that start between from and until,
+// in order: the expanded recurrence rule and RDATEs without the EXDATEs, with
+// overridden instances moved and cancelled ones left out. At most max
+// instances are returned.
+func (e *Event) Occurrences(from, until time.Time, max int) ([]Occurrence, error) {
+	duration := e.End.Sub(e.Start)
+	starts := []time.Time{e.Start}
+	if e.RRule != "" {
+		rule, err := parseRRule(e.RRule, e.Start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", e.UID, err)
+		}
+		starts = rule.expand(e.Start, from, until, max)
+	}
+	starts = append(starts, e.RDates...)
+
+	var out []Occurrence
+	seen := map[int64]bool{}
+	for _, s := range starts {
+		if seen[s.Unix()] || containsTime(e.ExDates, s) {
+			continue
+		}
+		seen[s.Unix()] = true
+		occ := Occurrence{Start: s, End: s.Add(duration)}
+		if o := e.override(s); o != nil {
+			if o.Status == "CANCELLED" {
+				continue
+			}
+			occ = Occurrence{Start: o.Start, End: o.End}
+		}
+		if occ.Start.Before(from) || occ.Start.After(until) {
+			continue
+		}
+		out = append(out, occ)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	if len(out) > max {
+		out = out[:max]
+	}
+	return out, nil
+}
+
+func (e *Event) override(t time.Time) *Override {
+	for _, o := range e.Overrides {
+		if o.RecurrenceID.Equal(t) {
+			return o
+		}
+	}
+	return nil
+}
+
+func containsTime(list []time.Time, t time.Time) bool {
+	for _, v := range list {
+		if v.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+type line struct {
+	num  int
+	text string
+}
+
+// unfold joins continuation lines (starting with a space or tab) to the line
+// before them.
+func unfold(r io.Reader) ([]line, error) {
+	var lines []line
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimRight(sc.Text(), "\r")
+		if n == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text != "" {
+			lines = append(lines, line{num: n, text: text})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a content line into its upper-cased name, parameters and
+// value.
+func parseLine(s string) (string, map[string]string, string, bool) {
+	inQuotes := false
+	colon := -1
+	for i, r := range s {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	head, value := s[:colon], s[colon+1:]
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+// parseTime parses a DATE or DATE-TIME value and reports whether it is a date.
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+		}
+		return t, false, nil
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return t, false, nil
+}
+
+// parseDuration parses a duration such as PT1H30M, P1D or -PT15M.
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			num = ""
+			switch {
+			case r == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	return sign * d, nil
+}
+
+// unescape decodes the escapes of a TEXT value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitEscaped splits a TEXT list on commas that are not escaped.
+func splitEscaped(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
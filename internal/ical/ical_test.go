@@ -0,0 +1,281 @@
+package ical
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var amsterdam = mustLoad("Europe/Amsterdam")
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// parseFile parses a calendar in testdata and returns its events by UID.
+func parseFile(t *testing.T, name string) map[string]*Event {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	events, err := Parse(f, amsterdam)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byUID := map[string]*Event{}
+	for _, e := range events {
+		byUID[e.UID] = e
+	}
+	return byUID
+}
+
+func TestParse(t *testing.T) {
+	events := parseFile(t, "recurring.ics")
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: the overrides belong to their event", len(events))
+	}
+
+	e := events["borrel-2026@example.nl"]
+	if e.Summary != "Vrijdagmiddagborrel" {
+		t.Errorf("summary %q: the override or the alarm replaced it", e.Summary)
+	}
+	if want := "Elke vrijdag in het foyer, met hapjes.\nToegang gratis. Aanmelden is niet nodig."; e.Description != want {
+		t.Errorf("description %q, want %q", e.Description, want)
+	}
+	if e.Location != "Foyer; eerste verdieping" {
+		t.Errorf("location %q", e.Location)
+	}
+	if !reflect.DeepEqual(e.Categories, []string{"Borrel", "Gratis,open"}) {
+		t.Errorf("categories %q", e.Categories)
+	}
+	if e.Start.Location().String() != "Europe/Amsterdam" || e.Start.Format(time.RFC3339) != "2026-03-13T17:00:00+01:00" {
+		t.Errorf("start %s in %s", e.Start.Format(time.RFC3339), e.Start.Location())
+	}
+	if e.End.Sub(e.Start) != 2*time.Hour || e.AllDay {
+		t.Errorf("end %s, all day %v", e.End, e.AllDay)
+	}
+	if len(e.ExDates) != 1 || len(e.Overrides) != 2 {
+		t.Errorf("%d exdates and %d overrides, want 1 and 2", len(e.ExDates), len(e.Overrides))
+	}
+
+	nyc := events["nyc@example.nl"]
+	if got := nyc.Start.UTC().Format(time.RFC3339); got != "2026-03-21T00:00:00Z" {
+		t.Errorf("New York start %s, want 2026-03-21T00:00:00Z", got)
+	}
+	if nyc.Status != "CONFIRMED" {
+		t.Errorf("status %q", nyc.Status)
+	}
+
+	tour := events["rondleiding@example.nl"]
+	if tour.End.Sub(tour.Start) != 90*time.Minute || tour.Start.Location() != time.UTC {
+		t.Errorf("tour runs from %s to %s", tour.Start, tour.End)
+	}
+
+	allDay := parseFile(t, "allday.ics")
+	festival := allDay["festival@example.nl"]
+	if !festival.AllDay || festival.Start.Format("2006-01-02") != "2026-07-10" || festival.End.Format("2006-01-02") != "2026-07-13" {
+		t.Errorf("festival: all day %v, %s to %s", festival.AllDay, festival.Start, festival.End)
+	}
+	if open := allDay["open-dag@example.nl"]; open.End.Sub(open.Start) != 24*time.Hour {
+		t.Errorf("all-day event without DTEND lasts %s, want a day", open.End.Sub(open.Start))
+	}
+	lezing := allDay["lezing@example.nl"]
+	if lezing.Start.Format(time.RFC3339) != "2026-05-05T19:30:00+02:00" || lezing.Status != "CANCELLED" {
+		t.Errorf("floating time %s, status %q", lezing.Start.Format(time.RFC3339), lezing.Status)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, ics, want string
+	}{
+		{"no uid", "BEGIN:VEVENT\nDTSTART:20260101T100000\nEND:VEVENT\n", "without UID"},
+		{"no start", "BEGIN:VEVENT\nUID:x\nEND:VEVENT\n", "has no DTSTART"},
+		{"bad start", "BEGIN:VEVENT\nUID:x\nDTSTART:2026-01-01\nEND:VEVENT\n", "DTSTART"},
+		{"bad duration", "BEGIN:VEVENT\nUID:x\nDTSTART:20260101T100000\nDURATION:1 hour\nEND:VEVENT\n", "DURATION"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader("BEGIN:VCALENDAR\n"+tt.ics+"END:VCALENDAR\n"), amsterdam)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// span formats occurrences as start/end pairs in Dutch local time.
+func span(occ []Occurrence) []string {
+	out := make([]string, len(occ))
+	for i, o := range occ {
+		out[i] = o.Start.In(amsterdam).Format("2006-01-02 15:04") + " - " + o.End.In(amsterdam).Format("15:04")
+	}
+	return out
+}
+
+func TestOccurrences(t *testing.T) {
+	recurring := parseFile(t, "recurring.ics")
+	allDay := parseFile(t, "allday.ics")
+	day := func(s string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02", s, amsterdam)
+		return t
+	}
+	tests := []struct {
+		name        string
+		event       *Event
+		from, until time.Time
+		max         int
+		want        []string
+	}{
+		{
+			// The 27th is moved to 18:00, the 3rd excluded, the 10th cancelled
+			// and the 17th, after the switch to summer time, stays at 17:00
+			// local time and just makes the UNTIL of 16:00 UTC.
+			name:  "weekly with EXDATE and RECURRENCE-ID",
+			event: recurring["borrel-2026@example.nl"],
+			from:  day("2026-01-01"), until: day("2026-12-31"), max: 100,
+			want: []string{
+				"2026-03-13 17:00 - 19:00",
+				"2026-03-20 17:00 - 19:00",
+				"2026-03-27 18:00 - 20:00",
+				"2026-04-17 17:00 - 19:00",
+			},
+		},
+		{
+			name:  "window from today",
+			event: recurring["borrel-2026@example.nl"],
+			from:  day("2026-03-21"), until: day("2026-04-01"), max: 100,
+			want: []string{"2026-03-27 18:00 - 20:00"},
+		},
+		{
+			name:  "max",
+			event: recurring["borrel-2026@example.nl"],
+			from:  day("2026-01-01"), until: day("2026-12-31"), max: 2,
+			want: []string{"2026-03-13 17:00 - 19:00", "2026-03-20 17:00 - 19:00"},
+		},
+		{
+			// First Sunday of the month at 13:00 UTC, four times, and an RDATE.
+			name:  "monthly with COUNT and RDATE",
+			event: recurring["rondleiding@example.nl"],
+			from:  day("2026-01-01"), until: day("2026-12-31"), max: 100,
+			want: []string{
+				"2026-03-01 14:00 - 15:30",
+				"2026-04-05 15:00 - 16:30",
+				"2026-05-03 15:00 - 16:30",
+				"2026-06-07 15:00 - 16:30",
+				"2026-06-15 15:00 - 16:30",
+			},
+		},
+		{
+			name:  "TZID of another zone",
+			event: recurring["nyc@example.nl"],
+			from:  day("2026-03-01"), until: day("2026-03-31"), max: 100,
+			want: []string{"2026-03-21 01:00 - 02:30"},
+		},
+		{
+			name:  "all-day span",
+			event: allDay["festival@example.nl"],
+			from:  day("2026-07-01"), until: day("2026-07-31"), max: 100,
+			want: []string{"2026-07-10 00:00 - 00:00"},
+		},
+		{
+			// DTSTART is the first instance; October 2026 is excluded.
+			name:  "yearly all-day with BYMONTH and EXDATE",
+			event: allDay["markt@example.nl"],
+			from:  day("2026-01-01"), until: day("2027-12-31"), max: 100,
+			want: []string{
+				"2026-01-01 00:00 - 00:00",
+				"2026-04-01 00:00 - 00:00",
+				"2027-04-01 00:00 - 00:00",
+				"2027-10-01 00:00 - 00:00",
+			},
+		},
+		{
+			name:  "outside the window",
+			event: allDay["open-dag@example.nl"],
+			from:  day("2026-10-01"), until: day("2026-12-31"), max: 100,
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occ, err := tt.event.Occurrences(tt.from, tt.until, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := span(occ); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestOccurrencesAllDayKeepsDays(t *testing.T) {
+	festival := parseFile(t, "allday.ics")["festival@example.nl"]
+	occ, err := festival.Occurrences(festival.Start, festival.Start.AddDate(0, 1, 0), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occ) != 1 || occ[0].End.Sub(occ[0].Start) != 72*time.Hour {
+		t.Errorf("occurrences %v, want one spanning three days", occ)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	at := func(s string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04", s, amsterdam)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	tests := []struct {
+		rule  string
+		start string
+		until string
+		max   int
+		want  []string
+	}{
+		{"FREQ=DAILY;COUNT=3", "2026-03-28 10:00", "2026-12-31 00:00", 100,
+			[]string{"2026-03-28 10:00 +0100", "2026-03-29 10:00 +0200", "2026-03-30 10:00 +0200"}},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=20260107T235959Z", "2026-01-01 09:00", "2026-12-31 00:00", 100,
+			[]string{"2026-01-01 09:00 +0100", "2026-01-03 09:00 +0100", "2026-01-05 09:00 +0100", "2026-01-07 09:00 +0100"}},
+		{"FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4", "2026-10-20 20:00", "2026-12-31 00:00", 100,
+			[]string{"2026-10-20 20:00 +0200", "2026-10-22 20:00 +0200", "2026-10-27 20:00 +0100", "2026-10-29 20:00 +0100"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31 12:00", "2026-06-30 00:00", 100,
+			[]string{"2026-01-31 12:00 +0100", "2026-03-31 12:00 +0200", "2026-05-31 12:00 +0200"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2026-01-30 16:00", "2026-04-30 00:00", 100,
+			[]string{"2026-01-30 16:00 +0100", "2026-02-27 16:00 +0100", "2026-03-27 16:00 +0100", "2026-04-24 16:00 +0200"}},
+		{"FREQ=YEARLY", "2024-02-29 12:00", "2029-01-01 00:00", 100,
+			[]string{"2024-02-29 12:00 +0100", "2028-02-29 12:00 +0100"}},
+		{"FREQ=WEEKLY", "2026-01-05 08:00", "2026-12-31 00:00", 3,
+			[]string{"2026-01-05 08:00 +0100", "2026-01-12 08:00 +0100", "2026-01-19 08:00 +0100"}},
+	}
+	for _, tt := range tests {
+		starts, err := Expand(tt.rule, at(tt.start), at(tt.until), tt.max)
+		if err != nil {
+			t.Errorf("%s: %v", tt.rule, err)
+			continue
+		}
+		got := make([]string, len(starts))
+		for i, s := range starts {
+			got[i] = s.Format("2006-01-02 15:04 -0700")
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s from %s:\n got %q\nwant %q", tt.rule, tt.start, got, tt.want)
+		}
+	}
+
+	for _, rule := range []string{"BYDAY=MO", "FREQ=HOURLY", "FREQ=WEEKLY;BYDAY=XX", "FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=DAILY;COUNT=x"} {
+		if _, err := Expand(rule, at("2026-01-01 00:00"), at("2026-02-01 00:00"), 10); err == nil {
+			t.Errorf("%s: expected an error", rule)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule is a parsed RRULE. BYSETPOS, BYWEEKNO, BYYEARDAY and the BYHOUR-style
+// parts are not supported; rules that use them are rejected.
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+}
+
+// weekdayNum is an entry of BYDAY: a weekday with an optional ordinal, e.g.
+// -1FR for the last Friday.
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRRule(s string, loc *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			var date bool
+			r.until, date, err = parseTime(v, nil, loc)
+			if date {
+				// A date includes the whole day.
+				r.until = r.until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				d = strings.ToUpper(strings.TrimSpace(d))
+				if len(d) < 2 {
+					err = fmt.Errorf("invalid day %q", d)
+					break
+				}
+				day, ok := weekdays[d[len(d)-2:]]
+				if !ok {
+					err = fmt.Errorf("invalid day %q", d)
+					break
+				}
+				wn := weekdayNum{day: day}
+				if num := d[:len(d)-2]; num != "" {
+					if wn.n, err = strconv.Atoi(num); err != nil {
+						break
+					}
+				}
+				r.byDay = append(r.byDay, wn)
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseInts(v, -31, 31)
+		case "BYMONTH":
+			r.byMonth, err = parseInts(v, 1, 12)
+		case "WKST":
+			// Weeks start on Monday; other week starts only matter for
+			// WEEKLY rules with an INTERVAL and several days, which are rare.
+		default:
+			return nil, fmt.Errorf("RRULE %s is not supported", strings.ToUpper(k))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE %s: %w", strings.ToUpper(k), err)
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE without FREQ")
+	default:
+		return nil, fmt.Errorf("RRULE FREQ=%s is not supported", r.freq)
+	}
+	return r, nil
+}
+
+// Expand returns the instances of the recurrence rule rule (the value of an
+// RRULE, e.g. FREQ=WEEKLY;BYDAY=FR;UNTIL=20261218) that start at start, up to
+// until. At most max instances are returned.
+func Expand(rule string, start, until time.Time, max int) ([]time.Time, error) {
+	r, err := parseRRule(rule, start.Location())
+	if err != nil {
+		return nil, err
+	}
+	return r.expand(start, start, until, max), nil
+}
+
+func parseInts(s string, min, max int) ([]int, error) {
+	var out []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < min || n > max || n == 0 {
+			return nil, fmt.Errorf("invalid value %q", part)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// expand returns the starts of the instances of the rule from start that fall
+// between from and limit. COUNT counts from start, which is always the first
+// instance. At most max starts are returned.
+func (r *rrule) expand(start, from, limit time.Time, max int) []time.Time {
+	if !r.until.IsZero() && r.until.Before(limit) {
+		limit = r.until
+	}
+
+	var out []time.Time
+	n := 0
+	emit := func(t time.Time) bool {
+		n++
+		if !t.Before(from) && !t.After(limit) {
+			out = append(out, t)
+		}
+		return (r.count > 0 && n >= r.count) || len(out) >= max
+	}
+	if emit(start) {
+		return out
+	}
+
+	// Generous bound for rules whose filters never match.
+	for period := 0; period < 100000; period++ {
+		candidates, periodStart := r.period(start, period)
+		if periodStart.After(limit) {
+			break
+		}
+		for _, t := range candidates {
+			if !t.After(start) {
+				continue
+			}
+			if t.After(limit) {
+				return out
+			}
+			if emit(t) {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// period returns the candidate starts of the given period (day, week, month or
+// year after start), in order, and the start of the period.
+func (r *rrule) period(start time.Time, period int) ([]time.Time, time.Time) {
+	step := period * r.interval
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+	switch r.freq {
+	case "DAILY":
+		day := at(start.Year(), start.Month(), start.Day()+step)
+		periodStart = day
+		if r.matchesDay(day) && r.matchesMonthDay(day) {
+			candidates = append(candidates, day)
+		}
+	case "WEEKLY":
+		offset := (int(start.Weekday()) + 6) % 7 // days since Monday
+		monday := at(start.Year(), start.Month(), start.Day()-offset+7*step)
+		periodStart = monday
+		days := r.byDay
+		if len(days) == 0 {
+			days = []weekdayNum{{day: start.Weekday()}}
+		}
+		for _, wd := range days {
+			candidates = append(candidates, monday.AddDate(0, 0, (int(wd.day)+6)%7))
+		}
+	case "MONTHLY":
+		first := at(start.Year(), start.Month()+time.Month(step), 1)
+		periodStart = first
+		candidates = r.monthDays(first, start.Day())
+	case "YEARLY":
+		year := start.Year() + step
+		periodStart = at(year, 1, 1)
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.monthDays(at(year, time.Month(m), 1), start.Day())...)
+		}
+	}
+
+	var out []time.Time
+	for _, t := range candidates {
+		if len(r.byMonth) == 0 || containsInt(r.byMonth, int(t.Month())) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out, periodStart
+}
+
+// monthDays returns the candidates in the month starting at first: the
+// BYMONTHDAY and BYDAY days, or day when neither is given.
+func (r *rrule) monthDays(first time.Time, day int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+	var out []time.Time
+	add := func(d int) {
+		if d >= 1 && d <= last {
+			out = append(out, first.AddDate(0, 0, d-1))
+		}
+	}
+
+	switch {
+	case len(r.byMonthDay) > 0:
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d = last + 1 + d
+			}
+			add(d)
+		}
+		if len(r.byDay) > 0 {
+			var filtered []time.Time
+			for _, t := range out {
+				if r.matchesDay(t) {
+					filtered = append(filtered, t)
+				}
+			}
+			out = filtered
+		}
+	case len(r.byDay) > 0:
+		for _, wd := range r.byDay {
+			firstOfDay := 1 + (int(wd.day)-int(first.Weekday())+7)%7
+			var days []int
+			for d := firstOfDay; d <= last; d += 7 {
+				days = append(days, d)
+			}
+			switch {
+			case wd.n == 0:
+				for _, d := range days {
+					add(d)
+				}
+			case wd.n > 0 && wd.n <= len(days):
+				add(days[wd.n-1])
+			case wd.n < 0 && -wd.n <= len(days):
+				add(days[len(days)+wd.n])
+			}
+		}
+	default:
+		// Months without the day (e.g. the 31st) are skipped, as the RFC requires.
+		add(day)
+	}
+	return out
+}
+
+func (r *rrule) matchesDay(t time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rrule) matchesMonthDay(t time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+	last := t.AddDate(0, 1, -t.Day()).Day()
+	for _, d := range r.byMonthDay {
+		if d == t.Day() || d < 0 && last+1+d == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
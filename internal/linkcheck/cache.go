@@ -0,0 +1,88 @@
+package linkcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores check results on disk so repeated runs don't hit the same URLs again
+// within TTL.
+type Cache struct {
+	path string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+// DefaultCachePath returns the cache file in the user's cache directory.
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding cache directory: %w", err)
+	}
+	return filepath.Join(dir, "tff-cli", "links.json"), nil
+}
+
+// OpenCache loads the cache at path. A missing file gives an empty cache.
+func OpenCache(path string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{path: path, ttl: ttl, results: map[string]Result{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading link cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.results); err != nil {
+		// A corrupt cache is not worth failing over; start fresh.
+		c.results = map[string]Result{}
+	}
+	return c, nil
+}
+
+// Get returns the cached result for url if it is younger than the TTL.
+func (c *Cache) Get(url string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, ok := c.results[url]
+	if !ok || time.Since(res.CheckedAt) > c.ttl {
+		return Result{}, false
+	}
+	return res, true
+}
+
+// Put stores a result.
+func (c *Cache) Put(res Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[res.URL] = res
+}
+
+// Save writes the cache to disk, dropping expired entries.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for u, res := range c.results {
+		if time.Since(res.CheckedAt) > c.ttl {
+			delete(c.results, u)
+		}
+	}
+
+	data, err := json.Marshal(c.results)
+	if err != nil {
+		return fmt.Errorf("encoding link cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("writing link cache: %w", err)
+	}
+	return nil
+}
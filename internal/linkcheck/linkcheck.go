@@ -0,0 +1,312 @@
+// Package linkcheck checks URLs concurrently, with per-host rate limits, redirect
+// tracking and an on-disk result cache.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Options configure a Checker.
+type Options struct {
+	// Timeout bounds each request, including redirects.
+	Timeout time.Duration
+	// Concurrency is the maximum number of requests in flight overall.
+	Concurrency int
+	// PerHost is the maximum number of requests in flight per host.
+	PerHost int
+	// HostDelay is the minimum time between the start of two requests to the same host.
+	HostDelay time.Duration
+	// MaxRedirects is the number of redirects followed before giving up.
+	MaxRedirects int
+	// UserAgent is sent with every request.
+	UserAgent string
+	// Cache, if set, is consulted before and updated after each check.
+	Cache *Cache
+}
+
+// Result is the outcome of checking one URL.
+type Result struct {
+	URL       string    `json:"url"`
+	Status    int       `json:"status,omitempty"`
+	FinalURL  string    `json:"finalUrl,omitempty"`
+	Redirects []string  `json:"redirects,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Broken    bool      `json:"broken"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Cached    bool      `json:"-"`
+}
+
+// Checker checks URLs.
+type Checker struct {
+	opts   Options
+	client *http.Client
+	// inFlight bounds the requests in flight over all hosts.
+	inFlight chan struct{}
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+type hostLimiter struct {
+	slots chan struct{}
+	mu    sync.Mutex
+	next  time.Time
+}
+
+// New returns a Checker. Zero options get sensible defaults.
+func New(opts Options) *Checker {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 16
+	}
+	if opts.PerHost <= 0 {
+		opts.PerHost = 2
+	}
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = 10
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = "tff-cli link checker"
+	}
+	return &Checker{
+		opts:     opts,
+		client:   &http.Client{},
+		inFlight: make(chan struct{}, opts.Concurrency),
+		hosts:    map[string]*hostLimiter{},
+	}
+}
+
+// CheckAll checks every URL once and returns the results keyed by URL. progress,
+// if not nil, is called after each URL with the number done so far. When ctx is
+// done, no new checks are started and only the completed results are returned.
+//
+// URLs are queued per host, each served by at most PerHost workers, so a slow or
+// rate-limited host only holds up its own URLs.
+func (c *Checker) CheckAll(ctx context.Context, urls []string, progress func(done, total int)) map[string]Result {
+	queues := map[string][]string{}
+	var hosts []string
+	total := 0
+	seen := map[string]bool{}
+	for _, u := range urls {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		total++
+		host := hostOf(u)
+		if _, ok := queues[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		queues[host] = append(queues[host], u)
+	}
+
+	results := make(map[string]Result, total)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done := 0
+
+	for _, host := range hosts {
+		jobs := make(chan string, len(queues[host]))
+		for _, u := range queues[host] {
+			jobs <- u
+		}
+		close(jobs)
+
+		for i := 0; i < min(c.opts.PerHost, len(queues[host])); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for u := range jobs {
+					if ctx.Err() != nil {
+						return
+					}
+					res := c.Check(ctx, u)
+					if ctx.Err() != nil {
+						// The check may have been cut short; don't report it.
+						return
+					}
+					mu.Lock()
+					results[u] = res
+					done++
+					if progress != nil {
+						progress(done, total)
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	return results
+}
+
+// hostOf returns the host a URL is queued under. URLs that don't parse share
+// the empty host; check reports them without a request.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// Check checks a single URL, using the cache when it holds a fresh result.
+func (c *Checker) Check(ctx context.Context, rawURL string) Result {
+	if c.opts.Cache != nil {
+		if res, ok := c.opts.Cache.Get(rawURL); ok {
+			res.Cached = true
+			return res
+		}
+	}
+
+	res := c.check(ctx, rawURL)
+	if c.opts.Cache != nil && ctx.Err() == nil {
+		c.opts.Cache.Put(res)
+	}
+	return res
+}
+
+func (c *Checker) check(ctx context.Context, rawURL string) Result {
+	res := Result{URL: rawURL, CheckedAt: time.Now().UTC()}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		res.Error = "invalid URL"
+		res.Broken = true
+		return res
+	}
+
+	limiter := c.hostLimiter(u.Host)
+	if err := acquire(ctx, limiter.slots); err != nil {
+		return cancelled(res, err)
+	}
+	defer func() { <-limiter.slots }()
+	if err := limiter.wait(ctx, c.opts.HostDelay); err != nil {
+		return cancelled(res, err)
+	}
+	if err := acquire(ctx, c.inFlight); err != nil {
+		return cancelled(res, err)
+	}
+	defer func() { <-c.inFlight }()
+
+	status, final, redirects, err := c.fetch(ctx, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		// Some servers don't answer HEAD properly; retry with GET before calling it broken.
+		status, final, redirects, err = c.fetch(ctx, http.MethodGet, rawURL)
+	}
+
+	res.Status = status
+	res.Redirects = redirects
+	if final != rawURL {
+		res.FinalURL = final
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.Broken = true
+	} else if status >= 400 {
+		res.Error = http.StatusText(status)
+		res.Broken = true
+	}
+	return res
+}
+
+// cancelled marks res as not checked because ctx ended first.
+func cancelled(res Result, err error) Result {
+	res.Error = err.Error()
+	res.Broken = true
+	return res
+}
+
+// acquire takes a slot from sem, or gives up when ctx is done.
+func acquire(ctx context.Context, sem chan struct{}) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Checker) fetch(ctx context.Context, method, rawURL string) (int, string, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, rawURL, nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+
+	var redirects []string
+	client := *c.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		redirects = append(redirects, req.URL.String())
+		if len(via) >= c.opts.MaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", c.opts.MaxRedirects)
+		}
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, rawURL, redirects, fmt.Errorf("timeout after %s", c.opts.Timeout)
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, rawURL, redirects, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, resp.Request.URL.String(), redirects, nil
+}
+
+func (c *Checker) hostLimiter(host string) *hostLimiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.hosts[host]
+	if !ok {
+		l = &hostLimiter{slots: make(chan struct{}, c.opts.PerHost)}
+		c.hosts[host] = l
+	}
+	return l
+}
+
+// wait blocks until at least delay has passed since the previous request to the
+// host, or until ctx is done.
+func (l *hostLimiter) wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(delay)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,160 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	var mu sync.Mutex
+	methods := map[string][]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods[r.URL.Path] = append(methods[r.URL.Path], r.Method)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(Options{MaxRedirects: 3})
+	tests := []struct {
+		path      string
+		status    int
+		broken    bool
+		final     string
+		redirects int
+		methods   []string
+	}{
+		{path: "/ok", status: 200, methods: []string{"HEAD"}},
+		{path: "/old", status: 200, final: "/ok", redirects: 2},
+		{path: "/missing", status: 404, broken: true},
+		{path: "/gone", status: 410, broken: true},
+		{path: "/no-head", status: 200, methods: []string{"HEAD", "GET"}},
+		{path: "/loop", broken: true, redirects: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res := c.Check(context.Background(), srv.URL+tt.path)
+			if res.Status != tt.status || res.Broken != tt.broken {
+				t.Errorf("status %d, broken %v; want %d, %v (error %q)", res.Status, res.Broken, tt.status, tt.broken, res.Error)
+			}
+			if tt.final != "" && res.FinalURL != srv.URL+tt.final {
+				t.Errorf("final URL %q, want %q", res.FinalURL, srv.URL+tt.final)
+			}
+			if len(res.Redirects) != tt.redirects {
+				t.Errorf("redirects %q, want %d", res.Redirects, tt.redirects)
+			}
+			if tt.methods != nil {
+				mu.Lock()
+				got := fmt.Sprint(methods[tt.path])
+				mu.Unlock()
+				if got != fmt.Sprint(tt.methods) {
+					t.Errorf("methods %s, want %s", got, tt.methods)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckInvalidURL(t *testing.T) {
+	for _, u := range []string{"ftp://example.com/file", "www.example.com", "http://"} {
+		res := New(Options{}).Check(context.Background(), u)
+		if !res.Broken || res.Error != "invalid URL" {
+			t.Errorf("%s: %+v", u, res)
+		}
+	}
+}
+
+// limitServer counts the requests in flight and records the most seen at once.
+type limitServer struct {
+	*httptest.Server
+	inFlight, most atomic.Int32
+}
+
+func newLimitServer(delay time.Duration) *limitServer {
+	s := &limitServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		for {
+			most := s.most.Load()
+			if n <= most || s.most.CompareAndSwap(most, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+	}))
+	return s
+}
+
+func TestCheckAllPerHostLimit(t *testing.T) {
+	slow := newLimitServer(20 * time.Millisecond)
+	defer slow.Close()
+	other := newLimitServer(0)
+	defer other.Close()
+
+	var urls []string
+	for i := 0; i < 12; i++ {
+		urls = append(urls, fmt.Sprintf("%s/%d", slow.URL, i), fmt.Sprintf("%s/%d", other.URL, i))
+	}
+	urls = append(urls, urls[0]) // duplicates are checked once
+
+	var calls atomic.Int32
+	c := New(Options{Concurrency: 8, PerHost: 2})
+	results := c.CheckAll(context.Background(), urls, func(done, total int) {
+		calls.Add(1)
+		if total != 24 {
+			t.Errorf("total %d, want 24", total)
+		}
+	})
+	if len(results) != 24 || calls.Load() != 24 {
+		t.Fatalf("%d results, %d progress calls; want 24", len(results), calls.Load())
+	}
+	for _, s := range []*limitServer{slow, other} {
+		if most := s.most.Load(); most > 2 {
+			t.Errorf("%s had %d requests in flight, want at most 2", s.URL, most)
+		}
+	}
+}
+
+func TestCheckAllHostDelayStopsWithContext(t *testing.T) {
+	srv := newLimitServer(0)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	c := New(Options{PerHost: 1, HostDelay: time.Hour})
+	start := time.Now()
+	results := c.CheckAll(ctx, []string{srv.URL + "/a", srv.URL + "/b", srv.URL + "/c"}, nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("CheckAll took %s after the context ended", elapsed)
+	}
+	if len(results) != 1 {
+		t.Errorf("%d results, want only the first URL checked before the delay", len(results))
+	}
+}
@@ -0,0 +1,251 @@
+// Package lint checks FeedFactory resources for data quality problems.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// Severity orders issues from informational to blocking.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// ParseSeverity parses "info", "warning" or "error".
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q (use info, warning or error)", s)
+}
+
+// Issue is a single problem found on a resource.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Type     string   `json:"type"`
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Owner    string   `json:"owner,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Context carries data that rules need besides the resource itself.
+type Context struct {
+	// Now is the reference time for date checks.
+	Now time.Time
+	// DeprecatedCategories holds the IDs of deprecated ontology categories.
+	DeprecatedCategories map[string]bool
+}
+
+// Rule is a single check. Check returns one message per problem found.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	// Types restricts the rule to these resource types (API endpoints). Empty means all.
+	Types []string
+	Check func(r *feedfactory.Resource, ctx *Context) []string
+}
+
+func (r *Rule) appliesTo(resourceType string) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, t := range r.Types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Config enables, disables and re-grades rules. It is read from a YAML file:
+//
+//	rules:
+//	  missing-coordinates:
+//	    enabled: false
+//	  empty-short-description:
+//	    severity: error
+type Config struct {
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+type RuleConfig struct {
+	Enabled  *bool  `yaml:"enabled"`
+	Severity string `yaml:"severity"`
+}
+
+// ConfigLocations returns the files searched for a lint config when none is given.
+func ConfigLocations() []string {
+	locations := []string{".tff-lint.yaml"}
+
+	homeDir, err := os.UserHomeDir()
+	if err == nil {
+		locations = append(locations, filepath.Join(homeDir, ".config", "tff-cli", "lint.yaml"))
+	}
+
+	return locations
+}
+
+// LoadConfig reads the lint config from path, or from the first of ConfigLocations
+// that exists when path is empty. No config file means all rules at default severity.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		for _, loc := range ConfigLocations() {
+			if _, err := os.Stat(loc); err == nil {
+				path = loc
+				break
+			}
+		}
+	}
+	if path == "" {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading lint config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing lint config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Linter runs the enabled rules over resources.
+type Linter struct {
+	rules []Rule
+	ctx   *Context
+}
+
+// New returns a linter with the built-in rules adjusted by cfg. Unknown rule IDs
+// and severities in cfg are reported as errors so typos don't silently disable checks.
+func New(cfg *Config, ctx *Context) (*Linter, error) {
+	rules, err := Configure(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var enabled []Rule
+	for _, r := range rules {
+		if r.Check != nil {
+			enabled = append(enabled, r)
+		}
+	}
+	return &Linter{rules: enabled, ctx: ctx}, nil
+}
+
+// Configure applies cfg to the built-in rules. Disabled rules are returned with a
+// nil Check so callers can still list them.
+func Configure(cfg *Config) ([]Rule, error) {
+	known := map[string]bool{}
+	for _, r := range Rules {
+		known[r.ID] = true
+	}
+	for id := range cfg.Rules {
+		if !known[id] {
+			return nil, fmt.Errorf("lint config: unknown rule %q", id)
+		}
+	}
+
+	rules := make([]Rule, 0, len(Rules))
+	for _, r := range Rules {
+		rc, ok := cfg.Rules[r.ID]
+		if ok {
+			if rc.Severity != "" {
+				sev, err := ParseSeverity(rc.Severity)
+				if err != nil {
+					return nil, fmt.Errorf("lint config: rule %q: %w", r.ID, err)
+				}
+				r.Severity = sev
+			}
+			if rc.Enabled != nil && !*rc.Enabled {
+				r.Check = nil
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Enabled reports whether the rule with the given ID is enabled.
+func (l *Linter) Enabled(id string) bool {
+	for _, r := range l.rules {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint runs all enabled rules that apply to resourceType over r.
+func (l *Linter) Lint(resourceType string, r *feedfactory.Resource) []Issue {
+	var issues []Issue
+	for _, rule := range l.rules {
+		if !rule.appliesTo(resourceType) {
+			continue
+		}
+		for _, msg := range rule.Check(r, l.ctx) {
+			issues = append(issues, Issue{
+				Rule:     rule.ID,
+				Severity: rule.Severity,
+				Type:     resourceType,
+				ID:       r.ID,
+				Title:    r.GetTitle(),
+				Owner:    r.Owner,
+				Message:  msg,
+			})
+		}
+	}
+	return issues
+}
+
+// SortIssues orders issues by severity (most severe first), then type, ID and rule.
+func SortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Rule < b.Rule
+	})
+}
@@ -0,0 +1,265 @@
+package lint
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// Rules are the built-in lint rules, in the order they are reported by --list-rules.
+var Rules = []Rule{
+	{
+		ID:          "missing-main-media",
+		Description: "Resource has no media item marked as main image.",
+		Severity:    Warning,
+		Check:       checkMainMedia,
+	},
+	{
+		ID:          "missing-coordinates",
+		Description: "Address has no latitude/longitude.",
+		Severity:    Warning,
+		Types:       []string{"events", "locations", "venues"},
+		Check:       checkCoordinates,
+	},
+	{
+		ID:          "no-future-dates",
+		Description: "Event has no dates today or in the future.",
+		Severity:    Warning,
+		Types:       []string{"events"},
+		Check:       checkFutureDates,
+	},
+	{
+		ID:          "end-before-start",
+		Description: "Event date ends before it starts, also when read as running past midnight.",
+		Severity:    Warning,
+		Types:       []string{"events"},
+		Check:       checkEndBeforeStart,
+	},
+	{
+		ID:          "published-draft",
+		Description: "Resource is published while its workflow status is still draft.",
+		Severity:    Error,
+		Check:       checkPublishedDraft,
+	},
+	{
+		ID:          "empty-short-description",
+		Description: "Resource has no short description in any language.",
+		Severity:    Warning,
+		Check:       checkShortDescription,
+	},
+	{
+		ID:          "invalid-url",
+		Description: "URL, contact URL or media URL is not a valid http(s) URL.",
+		Severity:    Error,
+		Check:       checkURLs,
+	},
+	{
+		ID:          "invalid-email",
+		Description: "Contact email address is not valid.",
+		Severity:    Error,
+		Check:       checkEmails,
+	},
+	{
+		ID:          "deprecated-category",
+		Description: "Resource uses a category that is deprecated in the ontology.",
+		Severity:    Warning,
+		Check:       checkDeprecatedCategories,
+	},
+}
+
+func checkMainMedia(r *feedfactory.Resource, _ *Context) []string {
+	if len(r.Media) == 0 {
+		return []string{"no media"}
+	}
+	for _, m := range r.Media {
+		if m.Main {
+			return nil
+		}
+	}
+	return []string{"no media item is marked as main"}
+}
+
+func checkCoordinates(r *feedfactory.Resource, _ *Context) []string {
+	if r.Location == nil || r.Location.Address == nil {
+		return []string{"no address"}
+	}
+	a := r.Location.Address
+	if a.Latitude == 0 && a.Longitude == 0 {
+		return []string{"address has no coordinates"}
+	}
+	return nil
+}
+
+func checkFutureDates(r *feedfactory.Resource, ctx *Context) []string {
+	cal := r.Calendar
+	if cal != nil && len(cal.PatternDates) > 0 {
+		// Pattern calendars are not expanded client-side.
+		return nil
+	}
+	if cal != nil {
+		t := strings.ToUpper(cal.CalendarType)
+		if strings.Contains(t, "ALWAYS") || strings.Contains(t, "REQUEST") {
+			return nil
+		}
+	}
+	if cal == nil || len(cal.SingleDates) == 0 {
+		return []string{"event has no dates"}
+	}
+
+	today := ctx.Now.In(feedfactory.APILocation).Format("2006-01-02")
+	last := ""
+	for _, d := range cal.SingleDates {
+		date := dateOnly(d.Date)
+		if date >= today {
+			return nil
+		}
+		if date > last {
+			last = date
+		}
+	}
+	return []string{fmt.Sprintf("last date %s is in the past", last)}
+}
+
+// maxOvernight is the longest span accepted for a date that ends before it
+// starts, which is read as running past midnight (22:00 to 02:00).
+const maxOvernight = 12 * 60
+
+func checkEndBeforeStart(r *feedfactory.Resource, _ *Context) []string {
+	if r.Calendar == nil {
+		return nil
+	}
+	var msgs []string
+	for _, d := range r.Calendar.SingleDates {
+		start, end := clockTime(d.StartTime), clockTime(d.EndTime)
+		from, ok1 := minutes(start)
+		to, ok2 := minutes(end)
+		if !ok1 || !ok2 || to >= from {
+			continue
+		}
+		if span := to + 24*60 - from; span > maxOvernight {
+			msgs = append(msgs, fmt.Sprintf("%s ends at %s before it starts at %s; overnight it would last %dh%02dm", dateOnly(d.Date), end, start, span/60, span%60))
+		}
+	}
+	return msgs
+}
+
+func checkPublishedDraft(r *feedfactory.Resource, _ *Context) []string {
+	if r.Published && strings.EqualFold(r.WFStatus, "draft") {
+		return []string{"published but workflow status is draft"}
+	}
+	return nil
+}
+
+func checkShortDescription(r *feedfactory.Resource, _ *Context) []string {
+	if strings.TrimSpace(r.GetShortDescription()) == "" {
+		return []string{"no short description"}
+	}
+	return nil
+}
+
+func checkURLs(r *feedfactory.Resource, _ *Context) []string {
+	var msgs []string
+	check := func(kind, raw string) {
+		if raw == "" {
+			return
+		}
+		if err := validateURL(raw); err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s %q: %v", kind, raw, err))
+		}
+	}
+	for _, u := range r.URLs {
+		check("url", u.URL)
+	}
+	if r.ContactInfo != nil {
+		for _, u := range r.ContactInfo.URLs {
+			check("contact url", u.URL)
+		}
+	}
+	for _, m := range r.Media {
+		check("media url", m.URL)
+	}
+	return msgs
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return fmt.Errorf("cannot be parsed")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" || !strings.Contains(u.Hostname(), ".") {
+		return fmt.Errorf("missing or invalid host")
+	}
+	return nil
+}
+
+func checkEmails(r *feedfactory.Resource, _ *Context) []string {
+	if r.ContactInfo == nil {
+		return nil
+	}
+	var emails []string
+	for _, m := range r.ContactInfo.Mails {
+		emails = append(emails, m.Email)
+	}
+	if len(emails) == 0 {
+		emails = append(emails, r.ContactInfo.GetEmail())
+	}
+
+	var msgs []string
+	for _, e := range emails {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(e)
+		if err != nil || addr.Address != e || !strings.Contains(e[strings.LastIndex(e, "@")+1:], ".") {
+			msgs = append(msgs, fmt.Sprintf("invalid email %q", e))
+		}
+	}
+	return msgs
+}
+
+func checkDeprecatedCategories(r *feedfactory.Resource, ctx *Context) []string {
+	var msgs []string
+	for _, id := range r.CategoryIDs() {
+		if ctx.DeprecatedCategories[id] {
+			msgs = append(msgs, fmt.Sprintf("category %s is deprecated", id))
+		}
+	}
+	return msgs
+}
+
+// dateOnly returns the yyyy-mm-dd part of a date or timestamp.
+func dateOnly(s string) string {
+	if len(s) > 10 {
+		return s[:10]
+	}
+	return s
+}
+
+// minutes returns the minutes since midnight of a clock time from clockTime.
+func minutes(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// clockTime normalizes "20:00" and "20:00:00" to "20:00" so times compare as strings.
+func clockTime(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 5 {
+		s = s[:5]
+	}
+	if len(s) == 4 && s[1] == ':' {
+		s = "0" + s
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
+)
+
+// TestRules runs each rule over documents that pass it and documents that
+// fail it, and checks the messages.
+func TestRules(t *testing.T) {
+	ctx := &Context{
+		// 22:30 UTC on 31 March is already 1 April in Amsterdam.
+		Now:                  time.Date(2026, 3, 31, 22, 30, 0, 0, time.UTC),
+		DeprecatedCategories: map[string]bool{"2.1.3": true},
+	}
+	tests := []struct {
+		rule string
+		doc  string
+		want []string
+	}{
+		{"missing-main-media", `{"media":[{"url":"https://example.nl/a.jpg"},{"url":"https://example.nl/b.jpg","main":true}]}`, nil},
+		{"missing-main-media", `{"media":[{"url":"https://example.nl/a.jpg"}]}`, []string{"no media item is marked as main"}},
+		{"missing-main-media", `{}`, []string{"no media"}},
+
+		{"missing-coordinates", `{"location":{"address":{"city":"Amsterdam","latitude":52.37,"longitude":4.89}}}`, nil},
+		{"missing-coordinates", `{"location":{"address":{"city":"Amsterdam"}}}`, []string{"address has no coordinates"}},
+		{"missing-coordinates", `{"location":{"label":"Online"}}`, []string{"no address"}},
+
+		{"no-future-dates", `{"calendar":{"singleDates":[{"date":"2026-03-01"},{"date":"2026-04-01T00:00:00"}]}}`, nil},
+		{"no-future-dates", `{"calendar":{"calendarType":"ALWAYSOPEN"}}`, nil},
+		{"no-future-dates", `{"calendar":{"patternDates":[{"recurrencyType":"weekly"}]}}`, nil},
+		{"no-future-dates", `{"calendar":{"singleDates":[{"date":"2026-03-31"},{"date":"2026-02-01"}]}}`, []string{"last date 2026-03-31 is in the past"}},
+		{"no-future-dates", `{"calendar":{"singleDates":[]}}`, []string{"event has no dates"}},
+
+		{"end-before-start", `{"calendar":{"singleDates":[{"date":"2026-04-01","starttime":"20:00","endtime":"22:00:00"},{"date":"2026-04-02","starttime":"22:00","endtime":"02:00"},{"date":"2026-04-03","starttime":"9:00"}]}}`, nil},
+		{"end-before-start", `{"calendar":{"singleDates":[{"date":"2026-04-01T00:00:00","starttime":"20:00:00","endtime":"08:30:00"}]}}`, []string{"2026-04-01 ends at 08:30 before it starts at 20:00; overnight it would last 12h30m"}},
+		{"end-before-start", `{"calendar":{"singleDates":[{"date":"2026-04-01","starttime":"14:00","endtime":"12:00"}]}}`, []string{"2026-04-01 ends at 12:00 before it starts at 14:00; overnight it would last 22h00m"}},
+
+		{"published-draft", `{"published":true,"wfstatus":"approved"}`, nil},
+		{"published-draft", `{"published":false,"wfstatus":"draft"}`, nil},
+		{"published-draft", `{"published":true,"wfstatus":"Draft"}`, []string{"published but workflow status is draft"}},
+
+		{"empty-short-description", `{"trcItemDetails":[{"lang":"nl","title":"Concert","shortdescription":"Live muziek"}]}`, nil},
+		{"empty-short-description", `{"trcItemDetails":[{"lang":"nl","title":"Concert","shortdescription":"  "}]}`, []string{"no short description"}},
+
+		{"invalid-url", `{"urls":[{"url":"https://example.nl"}],"contactinfo":{"urls":[{"url":"http://www.example.nl/contact"}]},"media":[{"url":""}]}`, nil},
+		{"invalid-url", `{"urls":[{"url":"www.example.nl"}],"contactinfo":{"urls":[{"url":"https://localhost/x"}]},"media":[{"url":"ftp://example.nl/a.jpg"}]}`, []string{
+			`url "www.example.nl": scheme must be http or https`,
+			`contact url "https://localhost/x": missing or invalid host`,
+			`media url "ftp://example.nl/a.jpg": scheme must be http or https`,
+		}},
+
+		{"invalid-email", `{"contactinfo":{"mails":[{"email":"info@example.nl"}]}}`, nil},
+		{"invalid-email", `{"contactinfo":{"mails":[{"email":"info@example"},{"email":"Info <info@example.nl>"},{"email":"info@@example.nl"}]}}`, []string{
+			`invalid email "info@example"`,
+			`invalid email "Info <info@example.nl>"`,
+			`invalid email "info@@example.nl"`,
+		}},
+
+		{"deprecated-category", `{"trcItemCategories":{"types":[{"catid":"2.1.1"}]}}`, nil},
+		{"deprecated-category", `{"types":["2.1.3"],"trcItemCategories":{"types":[{"catid":"2.1.1"}]}}`, []string{"category 2.1.3 is deprecated"}},
+	}
+
+	rules := map[string]Rule{}
+	for _, r := range Rules {
+		rules[r.ID] = r
+	}
+	tested := map[string]bool{}
+	for _, tt := range tests {
+		rule, ok := rules[tt.rule]
+		if !ok {
+			t.Fatalf("no rule %s", tt.rule)
+		}
+		tested[tt.rule] = true
+		var r feedfactory.Resource
+		if err := json.Unmarshal([]byte(tt.doc), &r); err != nil {
+			t.Fatalf("%s: %v", tt.doc, err)
+		}
+		if got := rule.Check(&r, ctx); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s on %s:\n got %q\nwant %q", tt.rule, tt.doc, got, tt.want)
+		}
+	}
+	for id := range rules {
+		if !tested[id] {
+			t.Errorf("rule %s has no test", id)
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	off := false
+	l, err := New(&Config{Rules: map[string]RuleConfig{
+		"missing-main-media": {Enabled: &off},
+		"published-draft":    {Severity: "warning"},
+	}}, &Context{Now: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r feedfactory.Resource
+	if err := json.Unmarshal([]byte(`{"id":"L1","published":true,"wfstatus":"draft","trcItemDetails":[{"lang":"nl","title":"Museum","shortdescription":"Kunst"}]}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	issues := l.Lint("locations", &r)
+	got := map[string]Severity{}
+	for _, is := range issues {
+		got[is.Rule] = is.Severity
+	}
+	want := map[string]Severity{"missing-coordinates": Warning, "published-draft": Warning}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues %v, want %v", got, want)
+	}
+
+	for _, cfg := range []*Config{
+		{Rules: map[string]RuleConfig{"no-such-rule": {}}},
+		{Rules: map[string]RuleConfig{"invalid-url": {Severity: "fatal"}}},
+	} {
+		if _, err := New(cfg, &Context{}); err == nil {
+			t.Errorf("config %+v: expected an error", cfg.Rules)
+		}
+	}
+}
@@ -1,29 +1,39 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/alecthomas/kong"
 	"github.com/TheFeedFactory/tff-cli/cmd"
-	"github.com/TheFeedFactory/tff-cli/internal/api"
+	"github.com/TheFeedFactory/tff-cli/feedfactory"
 	"github.com/TheFeedFactory/tff-cli/internal/config"
+	"github.com/alecthomas/kong"
 )
 
 var version = "0.2.0"
 
 var CLI struct {
-	Config string `short:"c" help:"Path to config file (.env format)." type:"path"`
-	Token  string `help:"Access token (overrides config file and environment variable)." env:"FF_ACCESS_TOKEN"`
+	Config  string        `short:"c" help:"Path to config file (.env format)." type:"path"`
+	Token   string        `help:"Access token (overrides config file and environment variable)." env:"FF_ACCESS_TOKEN"`
+	Output  string        `name:"output" enum:"table,wide,json,yaml,csv,ndjson,ids" default:"table" help:"Output format: table, wide (more columns), json, yaml, csv, ndjson (one JSON document per line) or ids (one ID per line). -j on a command is short for --output json."`
+	Columns []string      `name:"columns" help:"Comma-separated columns to show, e.g. id,title,owner. Run with --output wide to see the available columns. With json, yaml or ndjson output only these fields are included."`
+	Query   string        `name:"query" help:"jq expression applied to JSON output, e.g. '.results[].id' or '[.results[] | {id, wfstatus}]'. Switches table output to JSON. Runs in-process; jq does not need to be installed."`
+	Raw     bool          `name:"raw" help:"Print string results of --query without quotes, one per line (like jq -r)."`
+	Timeout time.Duration `name:"timeout" help:"Stop the command after this long, e.g. 30s or 10m. Bulk commands report how far they got. Default: no limit."`
+	TZ      string        `name:"tz" help:"Time zone for displaying timestamps, e.g. Europe/Amsterdam, UTC or Local (overrides FF_TIMEZONE). Default: Europe/Amsterdam."`
 
-	Events      cmd.EventsCmd      `cmd:"" help:"Manage events (list, get, export, delete, publish, unpublish, comments, revisions)."`
-	Locations   cmd.LocationsCmd   `cmd:"" help:"Manage locations (list, get, export, delete, publish, unpublish, comments, revisions)."`
-	Routes      cmd.RoutesCmd      `cmd:"" help:"Manage routes (list, get, export, delete, publish, unpublish, comments, revisions)."`
-	Venues      cmd.VenuesCmd      `cmd:"" help:"Manage venues (list, get, export, delete, publish, unpublish, comments, revisions)."`
-	EventGroups cmd.EventGroupsCmd `cmd:"" name:"eventgroups" help:"Manage event groups (list, get, export, delete, publish, unpublish, comments, revisions)."`
-	Dictionary  cmd.DictionaryCmd  `cmd:"" help:"Dictionary reference data (keywords, markers, ontology, categories)."`
-	Accounts    cmd.AccountsCmd    `cmd:"" help:"Account information (me, list)."`
-	Configure   ConfigureCmd       `cmd:"" help:"Show configuration help and setup instructions. Does not require authentication."`
+	Resources  cmd.Resources     `embed:""`
+	Dictionary cmd.DictionaryCmd `cmd:"" help:"Dictionary reference data (keywords, markers, ontology, categories)."`
+	Accounts   cmd.AccountsCmd   `cmd:"" help:"Account information (me, list)."`
+	Report     cmd.ReportCmd     `cmd:"" help:"Content reports across resources (translations, Excel workbook)."`
+	Check      cmd.CheckCmd      `cmd:"" help:"Check resources for problems that need network access (links)."`
+	Dedupe     cmd.DedupeCmd     `cmd:"" help:"Find likely duplicate locations, venues or events and optionally write a merge plan."`
+	Lint       cmd.LintCmd       `cmd:"" help:"Check resources for data quality issues. Exits non-zero when issues are found, for use in CI."`
+	Configure  ConfigureCmd      `cmd:"" help:"Show configuration help and setup instructions. Does not require authentication."`
 }
 
 type ConfigureCmd struct{}
@@ -63,14 +73,51 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		cfg = &config.Config{Token: CLI.Token}
+		cfg = &config.Config{Token: CLI.Token, TimeZone: os.Getenv("FF_TIMEZONE")}
 	}
 	if CLI.Token != "" {
 		cfg.Token = CLI.Token
 	}
+	if CLI.TZ != "" {
+		cfg.TimeZone = CLI.TZ
+	}
+	if err := cmd.SetDisplayTimeZone(cfg.TimeZone); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := cmd.SetOutput(CLI.Output, CLI.Columns); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := cmd.SetQuery(CLI.Query, CLI.Raw); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	client := api.NewClient(cfg)
+	client := feedfactory.New(
+		feedfactory.WithToken(cfg.Token),
+		feedfactory.WithUserAgent("tff-cli/"+version),
+	)
 
+	// Ctrl-C and SIGTERM cancel the running command, which stops at the next
+	// request. A second Ctrl-C exits immediately.
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-sigCtx.Done()
+		stop()
+	}()
+	runCtx := sigCtx
+	if CLI.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, CLI.Timeout)
+		defer cancel()
+	}
+
+	ctx.BindTo(runCtx, (*context.Context)(nil))
 	err = ctx.Run(client)
+	if err != nil && runCtx.Err() != nil {
+		err = cmd.StoppedError(runCtx, err)
+	}
 	ctx.FatalIfErrorf(err)
 }